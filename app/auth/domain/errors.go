@@ -0,0 +1,9 @@
+package domain
+
+import "errors"
+
+var (
+	ErrTokenMissing = errors.New("ErrTokenMissing")
+	ErrTokenInvalid = errors.New("ErrTokenInvalid")
+	ErrTokenExpired = errors.New("ErrTokenExpired")
+)
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	domain "todo-go-grpc/app/auth/domain"
+	response_service "todo-go-grpc/app/response_handler"
+
+	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
+)
+
+// Client sends token got from login in authorization metadata as "Bearer <token>"
+const (
+	metadataKey  = "authorization"
+	bearerPrefix = "Bearer "
+)
+
+type userIdKey struct{}
+
+// Get id of user authenticated by interceptor
+func UserId(ctx context.Context) (int32, bool) {
+	user_id, ok := ctx.Value(userIdKey{}).(int32)
+	return user_id, ok
+}
+
+func authenticate(ctx context.Context, secret []byte) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(metadataKey)
+	if len(values) == 0 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return nil, response_service.ResponseErrorUnauthenticated(domain.ErrTokenMissing)
+	}
+
+	user_id, err := ParseToken(secret, strings.TrimPrefix(values[0], bearerPrefix), time.Now())
+	if err != nil {
+		return nil, response_service.ResponseErrorUnauthenticated(err)
+	}
+	return context.WithValue(ctx, userIdKey{}, user_id), nil
+}
+
+// Reject unary call without valid token, user id of token is put in context of handler
+func UnaryInterceptor(secret []byte) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, secret)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Reject stream without valid token, user id of token is put in context of stream
+func StreamInterceptor(secret []byte) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), secret)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
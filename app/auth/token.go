@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "todo-go-grpc/app/auth/domain"
+)
+
+// Token is "<payload>.<signature>" in base64 url encoding, payload is "<user id>:<expires unix>"
+// and signature is HMAC-SHA256 of payload with secret shared by services
+func NewToken(secret []byte, user_id int32, expires_at time.Time) string {
+	payload := []byte(fmt.Sprintf("%d:%d", user_id, expires_at.Unix()))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sign(secret, payload))
+}
+
+// Get user id of token signed by secret which is not expired at now
+func ParseToken(secret []byte, token string, now time.Time) (int32, error) {
+	encoded_payload, encoded_signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, domain.ErrTokenInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded_payload)
+	if err != nil {
+		return 0, domain.ErrTokenInvalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encoded_signature)
+	if err != nil || !hmac.Equal(signature, sign(secret, payload)) {
+		return 0, domain.ErrTokenInvalid
+	}
+
+	user_text, expires_text, found := strings.Cut(string(payload), ":")
+	if !found {
+		return 0, domain.ErrTokenInvalid
+	}
+	user_id, err := strconv.ParseInt(user_text, 10, 32)
+	if err != nil || user_id <= 0 {
+		return 0, domain.ErrTokenInvalid
+	}
+	expires_at, err := strconv.ParseInt(expires_text, 10, 64)
+	if err != nil {
+		return 0, domain.ErrTokenInvalid
+	}
+	if !now.Before(time.Unix(expires_at, 0)) {
+		return 0, domain.ErrTokenExpired
+	}
+
+	return int32(user_id), nil
+}
+
+func sign(secret []byte, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
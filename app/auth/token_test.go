@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domain "todo-go-grpc/app/auth/domain"
+)
+
+func TestParseToken(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	valid := NewToken(secret, 7, now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		secret  []byte
+		token   string
+		now     time.Time
+		want    int32
+		wantErr error
+	}{
+		{name: "valid", secret: secret, token: valid, now: now, want: 7},
+		{name: "expired", secret: secret, token: valid, now: now.Add(time.Hour), wantErr: domain.ErrTokenExpired},
+		{name: "other secret", secret: []byte("other"), token: valid, now: now, wantErr: domain.ErrTokenInvalid},
+		{name: "tampered payload", secret: secret, token: NewToken([]byte("other"), 1, now.Add(time.Hour))[:4] + valid[4:], now: now, wantErr: domain.ErrTokenInvalid},
+		{name: "no signature", secret: secret, token: "Nzo5OTk5OTk5OTk5", now: now, wantErr: domain.ErrTokenInvalid},
+		{name: "empty", secret: secret, token: "", now: now, wantErr: domain.ErrTokenInvalid},
+		{name: "zero user", secret: secret, token: NewToken(secret, 0, now.Add(time.Hour)), now: now, wantErr: domain.ErrTokenInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseToken(test.secret, test.token, test.now)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("ParseToken() error = %v, want %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("ParseToken() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package dbservice
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Change of data or schema which AutoMigrate can not do, it runs once and its id is saved after it succeeds.
+// Id must not change once migration is released
+type Migration struct {
+	Id string
+	Up func(tx *gorm.DB) error
+}
+
+type schemaMigration struct {
+	Id        string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Run migrations which are not saved yet in order, each in its own transaction
+func runMigrations(db *gorm.DB, migrations []Migration) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Instances which start together wait for each other, so migration runs once
+			if err := tx.Exec("LOCK TABLE schema_migrations IN EXCLUSIVE MODE").Error; err != nil {
+				return err
+			}
+
+			var applied int64
+			if err := tx.Model(&schemaMigration{}).Where("id = ?", migration.Id).Count(&applied).Error; err != nil {
+				return err
+			}
+			if applied != 0 {
+				return nil
+			}
+
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Id: migration.Id, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
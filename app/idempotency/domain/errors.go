@@ -0,0 +1,8 @@
+package domain
+
+import "errors"
+
+var (
+	ErrKeyReused     = errors.New("ErrIdempotencyKeyReused")
+	ErrKeyInProgress = errors.New("ErrIdempotencyKeyInProgress")
+)
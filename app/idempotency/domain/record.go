@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// Response of the first request sent with key, replayed to retries until it expires
+type IdempotencyRecord struct {
+	// Method which key belongs to, for example task.Create
+	Scope       string `json:"scope" gorm:"column:scope;primaryKey"`
+	UserId      int32  `json:"user_id" gorm:"column:user_id;primaryKey;autoIncrement:false"`
+	Key         string `json:"key" gorm:"column:key;primaryKey"`
+	RequestHash string `json:"request_hash" gorm:"column:request_hash;not null"`
+	// Serialized response, empty while the first request is still running
+	Response  []byte    `json:"response" gorm:"column:response"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"column:expires_at;not null;index"`
+	// Claim without response is given to a retry after lease ends, so a crash does not hold key until it expires
+	LeaseExpiresAt time.Time `json:"lease_expires_at" gorm:"column:lease_expires_at;not null;default:now()"`
+}
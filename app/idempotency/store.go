@@ -0,0 +1,164 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"todo-go-grpc/app/dbservice"
+	domain "todo-go-grpc/app/idempotency/domain"
+
+	codes "google.golang.org/grpc/codes"
+	metadata "google.golang.org/grpc/metadata"
+	grpc_status "google.golang.org/grpc/status"
+	proto "google.golang.org/protobuf/proto"
+	"gorm.io/gorm/clause"
+)
+
+// Key can be sent in metadata instead of request
+const metadataKey = "idempotency-key"
+
+// Store keeps responses of requests sent with idempotency key for ttl, expired records are deleted every interval.
+// Request holds its key for lease while it runs, lease must be longer than the slowest request
+type Store struct {
+	Conn     dbservice.Database
+	ttl      time.Duration
+	lease    time.Duration
+	interval time.Duration
+}
+
+func NewStore(conn dbservice.Database, ttl time.Duration, lease time.Duration, interval time.Duration) *Store {
+	return &Store{
+		Conn:     conn,
+		ttl:      ttl,
+		lease:    lease,
+		interval: interval,
+	}
+}
+
+// Get key of request, key in metadata wins over key in request
+func Key(ctx context.Context, request_key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(metadataKey); len(values) != 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return request_key
+}
+
+func hashRequest(req proto.Message) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Run create once for key of user in scope. Retry with the same request gets the first response
+// unmarshaled into res, another request with the same key is rejected. Request is hashed as it is,
+// so key in request must be cleared by caller. Failed create frees key for the next retry,
+// key of request which never saved its response is freed when its lease ends
+func (s *Store) Do(ctx context.Context, scope string, user_id int32, key string, req proto.Message, res proto.Message, create func() (proto.Message, error)) (proto.Message, error) {
+	if key == "" {
+		return create()
+	}
+
+	hash, err := hashRequest(req)
+	if err != nil {
+		return nil, grpc_status.Error(codes.Internal, err.Error())
+	}
+
+	// Claim key, record of the same key is only replaced when it is expired
+	// or its request has not saved a response within lease
+	now := time.Now()
+	record := domain.IdempotencyRecord{
+		Scope:          scope,
+		UserId:         user_id,
+		Key:            key,
+		RequestHash:    hash,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.ttl),
+		LeaseExpiresAt: now.Add(s.lease),
+	}
+	result := s.Conn.Db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "user_id"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"request_hash":     hash,
+			"response":         nil,
+			"created_at":       now,
+			"expires_at":       now.Add(s.ttl),
+			"lease_expires_at": now.Add(s.lease),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL:  "idempotency_records.expires_at <= ? OR (idempotency_records.response IS NULL AND idempotency_records.lease_expires_at <= ?)",
+			Vars: []any{now, now},
+		}}},
+	}).Create(&record)
+	if result.Error != nil {
+		log.Println(result.Error.Error())
+		return nil, grpc_status.Error(codes.Unknown, result.Error.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return s.replay(scope, user_id, key, hash, res)
+	}
+
+	response, err := create()
+	if err != nil {
+		if delete_err := s.Conn.Db.Where("scope = ? AND user_id = ? AND key = ? AND request_hash = ?", scope, user_id, key, hash).Delete(&domain.IdempotencyRecord{}).Error; delete_err != nil {
+			log.Printf("Free idempotency key %v error: %v", key, delete_err)
+		}
+		return nil, err
+	}
+
+	// Response is already created, failed save only means a retry is rejected as in progress until lease ends
+	data, err := proto.Marshal(response)
+	if err == nil {
+		err = s.Conn.Db.Model(&domain.IdempotencyRecord{}).Where("scope = ? AND user_id = ? AND key = ?", scope, user_id, key).Update("response", data).Error
+	}
+	if err != nil {
+		log.Printf("Save response of idempotency key %v error: %v", key, err)
+	}
+
+	return response, nil
+}
+
+func (s *Store) replay(scope string, user_id int32, key string, hash string, res proto.Message) (proto.Message, error) {
+	var record domain.IdempotencyRecord
+	if err := s.Conn.Db.Where("scope = ? AND user_id = ? AND key = ?", scope, user_id, key).First(&record).Error; err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	if record.RequestHash != hash {
+		return nil, grpc_status.Error(codes.InvalidArgument, domain.ErrKeyReused.Error())
+	}
+	if record.Response == nil {
+		return nil, grpc_status.Error(codes.Aborted, domain.ErrKeyInProgress.Error())
+	}
+
+	if err := proto.Unmarshal(record.Response, res); err != nil {
+		return nil, grpc_status.Error(codes.Internal, err.Error())
+	}
+	return res, nil
+}
+
+// Run until ctx is done
+func (s *Store) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Conn.Db.Where("expires_at <= ?", time.Now()).Delete(&domain.IdempotencyRecord{}).Error; err != nil {
+				log.Printf("Delete expired idempotency keys error: %v", err)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package response_handler
 import (
 	"google.golang.org/grpc/codes"
 	grpc_status "google.golang.org/grpc/status"
+	protoiface "google.golang.org/protobuf/runtime/protoiface"
 )
 
 func ResponseErrorNotFound(err error) error {
@@ -28,3 +29,12 @@ func ResponseErrorAlreadyExists(err error) error {
 func ResponseErrorUnauthenticated(err error) error {
 	return grpc_status.Error(codes.Unauthenticated, err.Error())
 }
+
+// Abort with the current state of resource attached as details, so client can merge
+func ResponseErrorAborted(err error, details ...protoiface.MessageV1) error {
+	status := grpc_status.New(codes.Aborted, err.Error())
+	if status_details, detail_err := status.WithDetails(details...); detail_err == nil {
+		status = status_details
+	}
+	return status.Err()
+}
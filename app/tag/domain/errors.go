@@ -3,7 +3,8 @@ package domain
 import "errors"
 
 var (
-	ErrTagNotExists      = errors.New("ErrTagNotExists")
-	ErrTagIsExists       = errors.New("ErrTagIsExists")
-	ErrTagStillReference = errors.New("ErrTagStillReference")
+	ErrTagNotExists       = errors.New("ErrTagNotExists")
+	ErrTagIsExists        = errors.New("ErrTagIsExists")
+	ErrTagStillReference  = errors.New("ErrTagStillReference")
+	ErrTagVersionMismatch = errors.New("ErrTagVersionMismatch")
 )
@@ -7,4 +7,5 @@ type Tag struct {
 	Value       string    `form:"value" json:"value" gorm:"column:value;not null;unique"`
 	Description string    `form:"description" json:"description" gorm:"column:description"`
 	CreatedAt   time.Time `form:"-" json:"created_at" gorm:"column:created_at"`
+	Version     int32     `form:"version" json:"version" gorm:"column:version;not null;default:1"`
 }
@@ -4,24 +4,28 @@ import (
 	"context"
 	"errors"
 	"log"
+	"todo-go-grpc/app/idempotency"
 	response_service "todo-go-grpc/app/response_handler"
 	api "todo-go-grpc/app/tag/api"
 	domain "todo-go-grpc/app/tag/domain"
 	repository "todo-go-grpc/app/tag/repository"
 
 	"google.golang.org/grpc"
+	proto "google.golang.org/protobuf/proto"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type server struct {
-	repo repository.TagRepository
+	repo        repository.TagRepository
+	idempotency *idempotency.Store
 	api.UnimplementedTagHandlerServer
 }
 
-func RegisterGrpc(gserver *grpc.Server, repo repository.TagRepository) {
+func RegisterGrpc(gserver *grpc.Server, repo repository.TagRepository, idempotencyStore *idempotency.Store) {
 	tagServer := &server{
-		repo: repo,
+		repo:        repo,
+		idempotency: idempotencyStore,
 	}
 
 	api.RegisterTagHandlerServer(gserver, tagServer)
@@ -33,18 +37,30 @@ func transferDomainToProto(in domain.Tag) *api.Tag {
 		Value:       in.Value,
 		Description: in.Description,
 		CreatedTime: timestamppb.New(in.CreatedAt),
+		Version:     in.Version,
 	}
 }
 
-func transferProtoToDomain(in api.Tag) *domain.Tag {
+func transferProtoToDomain(in *api.Tag) *domain.Tag {
 	return &domain.Tag{
 		ID:          in.Id,
 		Description: in.Description,
 		Value:       in.Value,
 		CreatedAt:   in.CreatedTime.AsTime(),
+		Version:     in.Version,
 	}
 }
 
+// Abort with current state of tag attached, so client can merge
+func (serverInstance *server) responseVersionMismatch(ctx context.Context, err error, id int32) error {
+	tag, get_err := serverInstance.repo.GetByID(ctx, id)
+	if get_err != nil {
+		return response_service.ResponseErrorAborted(err)
+	}
+
+	return response_service.ResponseErrorAborted(err, transferDomainToProto(*tag))
+}
+
 func (serverInstance *server) List(ctx context.Context, req *api.ListReq) (*api.ListTag, error) {
 	if err := req.Valid(); err != nil {
 		return nil, response_service.ResponseErrorInvalidArgument(err)
@@ -88,20 +104,31 @@ func (serverInstance *server) Create(ctx context.Context, req *api.CreateReq) (*
 		return nil, response_service.ResponseErrorInvalidArgument(err)
 	}
 
-	new_tag, err := serverInstance.repo.Create(ctx, &domain.Tag{
-		Value:       req.Value,
-		Description: req.Description,
+	// Retry with the same idempotency key gets the tag created by the first request
+	request := proto.Clone(req).(*api.CreateReq)
+	request.IdempotencyKey = ""
+	res, err := serverInstance.idempotency.Do(ctx, "tag.Create", 0, idempotency.Key(ctx, req.IdempotencyKey), request, &api.Tag{}, func() (proto.Message, error) {
+		new_tag, err := serverInstance.repo.Create(ctx, &domain.Tag{
+			Value:       request.Value,
+			Description: request.Description,
+		})
+
+		if err != nil {
+			log.Println(err.Error())
+			if errors.Is(err, domain.ErrTagIsExists) {
+				return nil, response_service.ResponseErrorAlreadyExists(err)
+			}
+			return nil, response_service.ResponseErrorUnknown(err)
+		}
+
+		return transferDomainToProto(*new_tag), nil
 	})
 
 	if err != nil {
-		log.Println(err.Error())
-		if errors.Is(err, domain.ErrTagIsExists) {
-			return nil, response_service.ResponseErrorAlreadyExists(err)
-		}
-		return nil, response_service.ResponseErrorUnknown(err)
+		return nil, err
 	}
 
-	return transferDomainToProto(*new_tag), nil
+	return res.(*api.Tag), nil
 }
 
 func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*api.Tag, error) {
@@ -109,7 +136,8 @@ func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*
 		return nil, response_service.ResponseErrorInvalidArgument(err)
 	}
 
-	data := transferProtoToDomain(*req.NewTagInfo)
+	data := transferProtoToDomain(req.NewTagInfo)
+	data.Version = req.Version
 	new_tag, err := serverInstance.repo.Update(ctx, req.Id, data)
 
 	if err != nil {
@@ -117,6 +145,12 @@ func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*
 		if errors.Is(err, domain.ErrTagIsExists) {
 			return nil, response_service.ResponseErrorAlreadyExists(err)
 		}
+		if errors.Is(err, domain.ErrTagNotExists) {
+			return nil, response_service.ResponseErrorNotFound(err)
+		}
+		if errors.Is(err, domain.ErrTagVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, req.Id)
+		}
 		return nil, response_service.ResponseErrorUnknown(err)
 	}
 
@@ -128,12 +162,15 @@ func (serverInstance *server) Delete(ctx context.Context, req *api.DeleteReq) (*
 		return nil, response_service.ResponseErrorInvalidArgument(err)
 	}
 
-	err := serverInstance.repo.Delete(ctx, req.Id)
+	err := serverInstance.repo.Delete(ctx, req.Id, req.Version)
 
 	if err != nil {
 		if errors.Is(err, domain.ErrTagNotExists) {
 			return nil, response_service.ResponseErrorNotFound(err)
 		}
+		if errors.Is(err, domain.ErrTagVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, req.Id)
+		}
 		return nil, response_service.ResponseErrorUnknown(err)
 	}
 
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
 	"strconv"
+	"time"
 	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/idempotency"
 
 	"google.golang.org/grpc"
 
@@ -14,6 +17,10 @@ import (
 
 const (
 	port int = 8083
+
+	idempotencyTTL           time.Duration = 24 * time.Hour
+	idempotencyLease         time.Duration = time.Minute
+	idempotencyPurgeInterval time.Duration = time.Hour
 )
 
 func main() {
@@ -28,7 +35,10 @@ func main() {
 	db := dbservice.Init()
 
 	tagRepository := repo.NewTagRepository(*db)
-	service.RegisterGrpc(server, tagRepository)
+	idempotencyStore := idempotency.NewStore(*db, idempotencyTTL, idempotencyLease, idempotencyPurgeInterval)
+	service.RegisterGrpc(server, tagRepository, idempotencyStore)
+
+	go idempotencyStore.Start(context.Background())
 
 	log.Printf("Tag service start on port %v", port)
 	if err := server.Serve(listener); err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/jackc/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type tagRepository struct {
@@ -62,8 +63,17 @@ func (t *tagRepository) Update(ctx context.Context, id int32, new_info *domain.T
 	new_info_map := map[string]any{}
 	new_info_map["value"] = new_info.Value
 	new_info_map["description"] = new_info.Description
+	new_info_map["version"] = gorm.Expr("version + 1")
 
-	if err := t.Conn.Db.First(&new_info, id).Updates(new_info_map).Error; err != nil {
+	// Check version and update in one statement
+	var tag domain.Tag
+	query := t.Conn.Db.Model(&tag).Clauses(clause.Returning{}).Where("id = ?", id)
+	if new_info.Version != 0 {
+		query = query.Where("version = ?", new_info.Version)
+	}
+
+	result := query.Updates(new_info_map)
+	if err := result.Error; err != nil {
 		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
 			// Value of tag is duplicate
 			if pgError.Code == "23505" {
@@ -72,13 +82,21 @@ func (t *tagRepository) Update(ctx context.Context, id int32, new_info *domain.T
 		}
 		return nil, err
 	}
+	if result.RowsAffected == 0 {
+		return nil, t.checkVersion(id)
+	}
 
-	return new_info, nil
+	return &tag, nil
 }
 
-func (t *tagRepository) Delete(ctx context.Context, id int32) error {
-	tag := domain.Tag{ID: id}
-	if err := t.Conn.Db.Delete(&tag).Error; err != nil {
+func (t *tagRepository) Delete(ctx context.Context, id int32, version int32) error {
+	query := t.Conn.Db.Where("id = ?", id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&domain.Tag{})
+	if err := result.Error; err != nil {
 		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
 			// Tag still another reference
 			if pgError.Code == "23503" {
@@ -87,10 +105,26 @@ func (t *tagRepository) Delete(ctx context.Context, id int32) error {
 		}
 		return err
 	}
+	if version != 0 && result.RowsAffected == 0 {
+		return t.checkVersion(id)
+	}
 
 	return nil
 }
 
+// Find why a conditional statement did not touch the tag
+func (t *tagRepository) checkVersion(id int32) error {
+	var count int64
+	if err := t.Conn.Db.Model(&domain.Tag{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return domain.ErrTagNotExists
+	}
+	return domain.ErrTagVersionMismatch
+}
+
 func (t *tagRepository) DeleteAll(ctx context.Context) error {
 	return fmt.Errorf("Implemented needed")
 }
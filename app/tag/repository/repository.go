@@ -10,6 +10,6 @@ type TagRepository interface {
 	GetByID(ctx context.Context, id int32) (*domain.Tag, error)
 	Create(ctx context.Context, info *domain.Tag) (*domain.Tag, error)
 	Update(ctx context.Context, id int32, new_info *domain.Tag) (*domain.Tag, error)
-	Delete(ctx context.Context, id int32) error
+	Delete(ctx context.Context, id int32, version int32) error
 	DeleteAll(ctx context.Context) error
 }
@@ -27,6 +27,57 @@ type TaskHandlerClient interface {
 	Get(ctx context.Context, in *GetReq, opts ...grpc.CallOption) (*Task, error)
 	Create(ctx context.Context, in *CreateReq, opts ...grpc.CallOption) (*BasicTask, error)
 	Update(ctx context.Context, in *UpdateReq, opts ...grpc.CallOption) (*BasicTask, error)
+	// Items are written in one transaction, result of each item is in the order of items
+	BatchCreate(ctx context.Context, in *BatchCreateReq, opts ...grpc.CallOption) (*BatchRes, error)
+	BatchUpdate(ctx context.Context, in *BatchUpdateReq, opts ...grpc.CallOption) (*BatchRes, error)
+	MoveTask(ctx context.Context, in *MoveTaskReq, opts ...grpc.CallOption) (*BasicTask, error)
+	SkipOccurrence(ctx context.Context, in *SkipOccurrenceReq, opts ...grpc.CallOption) (*SkipOccurrenceRes, error)
+	UpdateSeries(ctx context.Context, in *UpdateSeriesReq, opts ...grpc.CallOption) (*Series, error)
+	StopSeries(ctx context.Context, in *StopSeriesReq, opts ...grpc.CallOption) (*Series, error)
+	AddReminder(ctx context.Context, in *AddReminderReq, opts ...grpc.CallOption) (*Reminder, error)
+	ListReminders(ctx context.Context, in *ListRemindersReq, opts ...grpc.CallOption) (*ListReminder, error)
+	SnoozeReminder(ctx context.Context, in *SnoozeReminderReq, opts ...grpc.CallOption) (*Reminder, error)
+	DeleteReminder(ctx context.Context, in *DeleteReminderReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AddComment(ctx context.Context, in *AddCommentReq, opts ...grpc.CallOption) (*Comment, error)
+	ListComments(ctx context.Context, in *ListCommentsReq, opts ...grpc.CallOption) (*ListComment, error)
+	EditComment(ctx context.Context, in *EditCommentReq, opts ...grpc.CallOption) (*Comment, error)
+	DeleteComment(ctx context.Context, in *DeleteCommentReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// First message carries info of attachment, the following ones carry its bytes
+	UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (TaskHandler_UploadAttachmentClient, error)
+	// First message carries info of attachment, the following ones carry its bytes
+	DownloadAttachment(ctx context.Context, in *DownloadAttachmentReq, opts ...grpc.CallOption) (TaskHandler_DownloadAttachmentClient, error)
+	ListAttachments(ctx context.Context, in *ListAttachmentsReq, opts ...grpc.CallOption) (*ListAttachment, error)
+	DeleteAttachment(ctx context.Context, in *DeleteAttachmentReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Share(ctx context.Context, in *ShareReq, opts ...grpc.CallOption) (*Collaborator, error)
+	Unshare(ctx context.Context, in *UnshareReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListCollaborators(ctx context.Context, in *ListCollaboratorsReq, opts ...grpc.CallOption) (*ListCollaborator, error)
+	AddDependency(ctx context.Context, in *AddDependencyReq, opts ...grpc.CallOption) (*Dependency, error)
+	RemoveDependency(ctx context.Context, in *RemoveDependencyReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListDependencies(ctx context.Context, in *ListDependenciesReq, opts ...grpc.CallOption) (*ListDependency, error)
+	CreateProject(ctx context.Context, in *CreateProjectReq, opts ...grpc.CallOption) (*Project, error)
+	GetProject(ctx context.Context, in *GetProjectReq, opts ...grpc.CallOption) (*Project, error)
+	ListProjects(ctx context.Context, in *ListProjectsReq, opts ...grpc.CallOption) (*ListProject, error)
+	UpdateProject(ctx context.Context, in *UpdateProjectReq, opts ...grpc.CallOption) (*Project, error)
+	DeleteProject(ctx context.Context, in *DeleteProjectReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	StartTimer(ctx context.Context, in *StartTimerReq, opts ...grpc.CallOption) (*TimeEntry, error)
+	StopTimer(ctx context.Context, in *StopTimerReq, opts ...grpc.CallOption) (*TimeEntry, error)
+	AddTimeEntry(ctx context.Context, in *AddTimeEntryReq, opts ...grpc.CallOption) (*TimeEntry, error)
+	TimeReport(ctx context.Context, in *TimeReportReq, opts ...grpc.CallOption) (*TimeReportRes, error)
+	CreateTemplate(ctx context.Context, in *CreateTemplateReq, opts ...grpc.CallOption) (*Template, error)
+	ListTemplates(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTemplate, error)
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	InstantiateTemplate(ctx context.Context, in *InstantiateTemplateReq, opts ...grpc.CallOption) (*Task, error)
+	Undo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UndoRes, error)
+	GetHistory(ctx context.Context, in *GetHistoryReq, opts ...grpc.CallOption) (*ListHistory, error)
+	// Stream changes of tasks caller can see until caller cancels
+	Watch(ctx context.Context, in *WatchReq, opts ...grpc.CallOption) (TaskHandler_WatchClient, error)
+	// Stream file of tasks created by caller in chunks
+	Export(ctx context.Context, in *ExportReq, opts ...grpc.CallOption) (TaskHandler_ExportClient, error)
+	// First message carries options of import, the following ones carry bytes of file
+	Import(ctx context.Context, opts ...grpc.CallOption) (TaskHandler_ImportClient, error)
+	ListTrash(ctx context.Context, in *ListTrashReq, opts ...grpc.CallOption) (*ListTask, error)
+	Restore(ctx context.Context, in *RestoreReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Purge(ctx context.Context, in *PurgeReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteMultiple(ctx context.Context, in *DeleteMultipleReq, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
@@ -75,140 +126,1685 @@ func (c *taskHandlerClient) Update(ctx context.Context, in *UpdateReq, opts ...g
 	return out, nil
 }
 
-func (c *taskHandlerClient) DeleteMultiple(ctx context.Context, in *DeleteMultipleReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *taskHandlerClient) BatchCreate(ctx context.Context, in *BatchCreateReq, opts ...grpc.CallOption) (*BatchRes, error) {
+	out := new(BatchRes)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) BatchUpdate(ctx context.Context, in *BatchUpdateReq, opts ...grpc.CallOption) (*BatchRes, error) {
+	out := new(BatchRes)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) MoveTask(ctx context.Context, in *MoveTaskReq, opts ...grpc.CallOption) (*BasicTask, error) {
+	out := new(BasicTask)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/MoveTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) SkipOccurrence(ctx context.Context, in *SkipOccurrenceReq, opts ...grpc.CallOption) (*SkipOccurrenceRes, error) {
+	out := new(SkipOccurrenceRes)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/SkipOccurrence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) UpdateSeries(ctx context.Context, in *UpdateSeriesReq, opts ...grpc.CallOption) (*Series, error) {
+	out := new(Series)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/UpdateSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) StopSeries(ctx context.Context, in *StopSeriesReq, opts ...grpc.CallOption) (*Series, error) {
+	out := new(Series)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/StopSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) AddReminder(ctx context.Context, in *AddReminderReq, opts ...grpc.CallOption) (*Reminder, error) {
+	out := new(Reminder)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/AddReminder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListReminders(ctx context.Context, in *ListRemindersReq, opts ...grpc.CallOption) (*ListReminder, error) {
+	out := new(ListReminder)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListReminders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) SnoozeReminder(ctx context.Context, in *SnoozeReminderReq, opts ...grpc.CallOption) (*Reminder, error) {
+	out := new(Reminder)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/SnoozeReminder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteReminder(ctx context.Context, in *DeleteReminderReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteMultiple", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteReminder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) AddComment(ctx context.Context, in *AddCommentReq, opts ...grpc.CallOption) (*Comment, error) {
+	out := new(Comment)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/AddComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListComments(ctx context.Context, in *ListCommentsReq, opts ...grpc.CallOption) (*ListComment, error) {
+	out := new(ListComment)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) EditComment(ctx context.Context, in *EditCommentReq, opts ...grpc.CallOption) (*Comment, error) {
+	out := new(Comment)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/EditComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteComment(ctx context.Context, in *DeleteCommentReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) UploadAttachment(ctx context.Context, opts ...grpc.CallOption) (TaskHandler_UploadAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskHandler_ServiceDesc.Streams[0], "/api.task.TaskHandler/UploadAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskHandlerUploadAttachmentClient{stream}
+	return x, nil
+}
+
+type TaskHandler_UploadAttachmentClient interface {
+	Send(*UploadAttachmentReq) error
+	CloseAndRecv() (*Attachment, error)
+	grpc.ClientStream
+}
+
+type taskHandlerUploadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskHandlerUploadAttachmentClient) Send(m *UploadAttachmentReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *taskHandlerUploadAttachmentClient) CloseAndRecv() (*Attachment, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Attachment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskHandlerClient) DownloadAttachment(ctx context.Context, in *DownloadAttachmentReq, opts ...grpc.CallOption) (TaskHandler_DownloadAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskHandler_ServiceDesc.Streams[1], "/api.task.TaskHandler/DownloadAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskHandlerDownloadAttachmentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskHandler_DownloadAttachmentClient interface {
+	Recv() (*DownloadAttachmentRes, error)
+	grpc.ClientStream
+}
+
+type taskHandlerDownloadAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskHandlerDownloadAttachmentClient) Recv() (*DownloadAttachmentRes, error) {
+	m := new(DownloadAttachmentRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskHandlerClient) ListAttachments(ctx context.Context, in *ListAttachmentsReq, opts ...grpc.CallOption) (*ListAttachment, error) {
+	out := new(ListAttachment)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListAttachments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteAttachment(ctx context.Context, in *DeleteAttachmentReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteAttachment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) Share(ctx context.Context, in *ShareReq, opts ...grpc.CallOption) (*Collaborator, error) {
+	out := new(Collaborator)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/Share", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) Unshare(ctx context.Context, in *UnshareReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/Unshare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListCollaborators(ctx context.Context, in *ListCollaboratorsReq, opts ...grpc.CallOption) (*ListCollaborator, error) {
+	out := new(ListCollaborator)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListCollaborators", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) AddDependency(ctx context.Context, in *AddDependencyReq, opts ...grpc.CallOption) (*Dependency, error) {
+	out := new(Dependency)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/AddDependency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) RemoveDependency(ctx context.Context, in *RemoveDependencyReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/RemoveDependency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListDependencies(ctx context.Context, in *ListDependenciesReq, opts ...grpc.CallOption) (*ListDependency, error) {
+	out := new(ListDependency)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListDependencies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) CreateProject(ctx context.Context, in *CreateProjectReq, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/CreateProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) GetProject(ctx context.Context, in *GetProjectReq, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/GetProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListProjects(ctx context.Context, in *ListProjectsReq, opts ...grpc.CallOption) (*ListProject, error) {
+	out := new(ListProject)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListProjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) UpdateProject(ctx context.Context, in *UpdateProjectReq, opts ...grpc.CallOption) (*Project, error) {
+	out := new(Project)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/UpdateProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteProject(ctx context.Context, in *DeleteProjectReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteProject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) StartTimer(ctx context.Context, in *StartTimerReq, opts ...grpc.CallOption) (*TimeEntry, error) {
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/StartTimer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) StopTimer(ctx context.Context, in *StopTimerReq, opts ...grpc.CallOption) (*TimeEntry, error) {
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/StopTimer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) AddTimeEntry(ctx context.Context, in *AddTimeEntryReq, opts ...grpc.CallOption) (*TimeEntry, error) {
+	out := new(TimeEntry)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/AddTimeEntry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) TimeReport(ctx context.Context, in *TimeReportReq, opts ...grpc.CallOption) (*TimeReportRes, error) {
+	out := new(TimeReportRes)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/TimeReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) CreateTemplate(ctx context.Context, in *CreateTemplateReq, opts ...grpc.CallOption) (*Template, error) {
+	out := new(Template)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/CreateTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) ListTemplates(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTemplate, error) {
+	out := new(ListTemplate)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) InstantiateTemplate(ctx context.Context, in *InstantiateTemplateReq, opts ...grpc.CallOption) (*Task, error) {
+	out := new(Task)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/InstantiateTemplate", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *taskHandlerClient) Undo(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*UndoRes, error) {
+	out := new(UndoRes)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/Undo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) GetHistory(ctx context.Context, in *GetHistoryReq, opts ...grpc.CallOption) (*ListHistory, error) {
+	out := new(ListHistory)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/GetHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) Watch(ctx context.Context, in *WatchReq, opts ...grpc.CallOption) (TaskHandler_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskHandler_ServiceDesc.Streams[2], "/api.task.TaskHandler/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskHandlerWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskHandler_WatchClient interface {
+	Recv() (*WatchEvent, error)
+	grpc.ClientStream
+}
+
+type taskHandlerWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskHandlerWatchClient) Recv() (*WatchEvent, error) {
+	m := new(WatchEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskHandlerClient) Export(ctx context.Context, in *ExportReq, opts ...grpc.CallOption) (TaskHandler_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskHandler_ServiceDesc.Streams[3], "/api.task.TaskHandler/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskHandlerExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaskHandler_ExportClient interface {
+	Recv() (*ExportChunk, error)
+	grpc.ClientStream
+}
+
+type taskHandlerExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskHandlerExportClient) Recv() (*ExportChunk, error) {
+	m := new(ExportChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskHandlerClient) Import(ctx context.Context, opts ...grpc.CallOption) (TaskHandler_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaskHandler_ServiceDesc.Streams[4], "/api.task.TaskHandler/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taskHandlerImportClient{stream}
+	return x, nil
+}
+
+type TaskHandler_ImportClient interface {
+	Send(*ImportReq) error
+	CloseAndRecv() (*ImportRes, error)
+	grpc.ClientStream
+}
+
+type taskHandlerImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *taskHandlerImportClient) Send(m *ImportReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *taskHandlerImportClient) CloseAndRecv() (*ImportRes, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taskHandlerClient) ListTrash(ctx context.Context, in *ListTrashReq, opts ...grpc.CallOption) (*ListTask, error) {
+	out := new(ListTask)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/ListTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) Restore(ctx context.Context, in *RestoreReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) Purge(ctx context.Context, in *PurgeReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteMultiple(ctx context.Context, in *DeleteMultipleReq, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteMultiple", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskHandlerClient) DeleteAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaskHandlerServer is the server API for TaskHandler service.
+// All implementations must embed UnimplementedTaskHandlerServer
+// for forward compatibility
+type TaskHandlerServer interface {
+	List(context.Context, *ListReq) (*ListTask, error)
+	Get(context.Context, *GetReq) (*Task, error)
+	Create(context.Context, *CreateReq) (*BasicTask, error)
+	Update(context.Context, *UpdateReq) (*BasicTask, error)
+	// Items are written in one transaction, result of each item is in the order of items
+	BatchCreate(context.Context, *BatchCreateReq) (*BatchRes, error)
+	BatchUpdate(context.Context, *BatchUpdateReq) (*BatchRes, error)
+	MoveTask(context.Context, *MoveTaskReq) (*BasicTask, error)
+	SkipOccurrence(context.Context, *SkipOccurrenceReq) (*SkipOccurrenceRes, error)
+	UpdateSeries(context.Context, *UpdateSeriesReq) (*Series, error)
+	StopSeries(context.Context, *StopSeriesReq) (*Series, error)
+	AddReminder(context.Context, *AddReminderReq) (*Reminder, error)
+	ListReminders(context.Context, *ListRemindersReq) (*ListReminder, error)
+	SnoozeReminder(context.Context, *SnoozeReminderReq) (*Reminder, error)
+	DeleteReminder(context.Context, *DeleteReminderReq) (*emptypb.Empty, error)
+	AddComment(context.Context, *AddCommentReq) (*Comment, error)
+	ListComments(context.Context, *ListCommentsReq) (*ListComment, error)
+	EditComment(context.Context, *EditCommentReq) (*Comment, error)
+	DeleteComment(context.Context, *DeleteCommentReq) (*emptypb.Empty, error)
+	// First message carries info of attachment, the following ones carry its bytes
+	UploadAttachment(TaskHandler_UploadAttachmentServer) error
+	// First message carries info of attachment, the following ones carry its bytes
+	DownloadAttachment(*DownloadAttachmentReq, TaskHandler_DownloadAttachmentServer) error
+	ListAttachments(context.Context, *ListAttachmentsReq) (*ListAttachment, error)
+	DeleteAttachment(context.Context, *DeleteAttachmentReq) (*emptypb.Empty, error)
+	Share(context.Context, *ShareReq) (*Collaborator, error)
+	Unshare(context.Context, *UnshareReq) (*emptypb.Empty, error)
+	ListCollaborators(context.Context, *ListCollaboratorsReq) (*ListCollaborator, error)
+	AddDependency(context.Context, *AddDependencyReq) (*Dependency, error)
+	RemoveDependency(context.Context, *RemoveDependencyReq) (*emptypb.Empty, error)
+	ListDependencies(context.Context, *ListDependenciesReq) (*ListDependency, error)
+	CreateProject(context.Context, *CreateProjectReq) (*Project, error)
+	GetProject(context.Context, *GetProjectReq) (*Project, error)
+	ListProjects(context.Context, *ListProjectsReq) (*ListProject, error)
+	UpdateProject(context.Context, *UpdateProjectReq) (*Project, error)
+	DeleteProject(context.Context, *DeleteProjectReq) (*emptypb.Empty, error)
+	StartTimer(context.Context, *StartTimerReq) (*TimeEntry, error)
+	StopTimer(context.Context, *StopTimerReq) (*TimeEntry, error)
+	AddTimeEntry(context.Context, *AddTimeEntryReq) (*TimeEntry, error)
+	TimeReport(context.Context, *TimeReportReq) (*TimeReportRes, error)
+	CreateTemplate(context.Context, *CreateTemplateReq) (*Template, error)
+	ListTemplates(context.Context, *emptypb.Empty) (*ListTemplate, error)
+	DeleteTemplate(context.Context, *DeleteTemplateReq) (*emptypb.Empty, error)
+	InstantiateTemplate(context.Context, *InstantiateTemplateReq) (*Task, error)
+	Undo(context.Context, *emptypb.Empty) (*UndoRes, error)
+	GetHistory(context.Context, *GetHistoryReq) (*ListHistory, error)
+	// Stream changes of tasks caller can see until caller cancels
+	Watch(*WatchReq, TaskHandler_WatchServer) error
+	// Stream file of tasks created by caller in chunks
+	Export(*ExportReq, TaskHandler_ExportServer) error
+	// First message carries options of import, the following ones carry bytes of file
+	Import(TaskHandler_ImportServer) error
+	ListTrash(context.Context, *ListTrashReq) (*ListTask, error)
+	Restore(context.Context, *RestoreReq) (*emptypb.Empty, error)
+	Purge(context.Context, *PurgeReq) (*emptypb.Empty, error)
+	DeleteMultiple(context.Context, *DeleteMultipleReq) (*emptypb.Empty, error)
+	DeleteAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	mustEmbedUnimplementedTaskHandlerServer()
+}
+
+// UnimplementedTaskHandlerServer must be embedded to have forward compatible implementations.
+type UnimplementedTaskHandlerServer struct {
+}
+
+func (UnimplementedTaskHandlerServer) List(context.Context, *ListReq) (*ListTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedTaskHandlerServer) Get(context.Context, *GetReq) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedTaskHandlerServer) Create(context.Context, *CreateReq) (*BasicTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedTaskHandlerServer) Update(context.Context, *UpdateReq) (*BasicTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedTaskHandlerServer) BatchCreate(context.Context, *BatchCreateReq) (*BatchRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreate not implemented")
+}
+func (UnimplementedTaskHandlerServer) BatchUpdate(context.Context, *BatchUpdateReq) (*BatchRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdate not implemented")
+}
+func (UnimplementedTaskHandlerServer) MoveTask(context.Context, *MoveTaskReq) (*BasicTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveTask not implemented")
+}
+func (UnimplementedTaskHandlerServer) SkipOccurrence(context.Context, *SkipOccurrenceReq) (*SkipOccurrenceRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SkipOccurrence not implemented")
+}
+func (UnimplementedTaskHandlerServer) UpdateSeries(context.Context, *UpdateSeriesReq) (*Series, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSeries not implemented")
+}
+func (UnimplementedTaskHandlerServer) StopSeries(context.Context, *StopSeriesReq) (*Series, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopSeries not implemented")
+}
+func (UnimplementedTaskHandlerServer) AddReminder(context.Context, *AddReminderReq) (*Reminder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddReminder not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListReminders(context.Context, *ListRemindersReq) (*ListReminder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReminders not implemented")
+}
+func (UnimplementedTaskHandlerServer) SnoozeReminder(context.Context, *SnoozeReminderReq) (*Reminder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnoozeReminder not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteReminder(context.Context, *DeleteReminderReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteReminder not implemented")
+}
+func (UnimplementedTaskHandlerServer) AddComment(context.Context, *AddCommentReq) (*Comment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListComments(context.Context, *ListCommentsReq) (*ListComment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedTaskHandlerServer) EditComment(context.Context, *EditCommentReq) (*Comment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditComment not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteComment(context.Context, *DeleteCommentReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComment not implemented")
+}
+func (UnimplementedTaskHandlerServer) UploadAttachment(TaskHandler_UploadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAttachment not implemented")
+}
+func (UnimplementedTaskHandlerServer) DownloadAttachment(*DownloadAttachmentReq, TaskHandler_DownloadAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadAttachment not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListAttachments(context.Context, *ListAttachmentsReq) (*ListAttachment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAttachments not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteAttachment(context.Context, *DeleteAttachmentReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAttachment not implemented")
+}
+func (UnimplementedTaskHandlerServer) Share(context.Context, *ShareReq) (*Collaborator, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Share not implemented")
+}
+func (UnimplementedTaskHandlerServer) Unshare(context.Context, *UnshareReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unshare not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListCollaborators(context.Context, *ListCollaboratorsReq) (*ListCollaborator, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCollaborators not implemented")
+}
+func (UnimplementedTaskHandlerServer) AddDependency(context.Context, *AddDependencyReq) (*Dependency, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDependency not implemented")
+}
+func (UnimplementedTaskHandlerServer) RemoveDependency(context.Context, *RemoveDependencyReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDependency not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListDependencies(context.Context, *ListDependenciesReq) (*ListDependency, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDependencies not implemented")
+}
+func (UnimplementedTaskHandlerServer) CreateProject(context.Context, *CreateProjectReq) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProject not implemented")
+}
+func (UnimplementedTaskHandlerServer) GetProject(context.Context, *GetProjectReq) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProject not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListProjects(context.Context, *ListProjectsReq) (*ListProject, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (UnimplementedTaskHandlerServer) UpdateProject(context.Context, *UpdateProjectReq) (*Project, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProject not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteProject(context.Context, *DeleteProjectReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProject not implemented")
+}
+func (UnimplementedTaskHandlerServer) StartTimer(context.Context, *StartTimerReq) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartTimer not implemented")
+}
+func (UnimplementedTaskHandlerServer) StopTimer(context.Context, *StopTimerReq) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopTimer not implemented")
+}
+func (UnimplementedTaskHandlerServer) AddTimeEntry(context.Context, *AddTimeEntryReq) (*TimeEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTimeEntry not implemented")
+}
+func (UnimplementedTaskHandlerServer) TimeReport(context.Context, *TimeReportReq) (*TimeReportRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeReport not implemented")
+}
+func (UnimplementedTaskHandlerServer) CreateTemplate(context.Context, *CreateTemplateReq) (*Template, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListTemplates(context.Context, *emptypb.Empty) (*ListTemplate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteTemplate(context.Context, *DeleteTemplateReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedTaskHandlerServer) InstantiateTemplate(context.Context, *InstantiateTemplateReq) (*Task, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstantiateTemplate not implemented")
+}
+func (UnimplementedTaskHandlerServer) Undo(context.Context, *emptypb.Empty) (*UndoRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Undo not implemented")
+}
+func (UnimplementedTaskHandlerServer) GetHistory(context.Context, *GetHistoryReq) (*ListHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedTaskHandlerServer) Watch(*WatchReq, TaskHandler_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTaskHandlerServer) Export(*ExportReq, TaskHandler_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedTaskHandlerServer) Import(TaskHandler_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedTaskHandlerServer) ListTrash(context.Context, *ListTrashReq) (*ListTask, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedTaskHandlerServer) Restore(context.Context, *RestoreReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedTaskHandlerServer) Purge(context.Context, *PurgeReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteMultiple(context.Context, *DeleteMultipleReq) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMultiple not implemented")
+}
+func (UnimplementedTaskHandlerServer) DeleteAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAll not implemented")
+}
+func (UnimplementedTaskHandlerServer) mustEmbedUnimplementedTaskHandlerServer() {}
+
+// UnsafeTaskHandlerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskHandlerServer will
+// result in compilation errors.
+type UnsafeTaskHandlerServer interface {
+	mustEmbedUnimplementedTaskHandlerServer()
+}
+
+func RegisterTaskHandlerServer(s grpc.ServiceRegistrar, srv TaskHandlerServer) {
+	s.RegisterService(&TaskHandler_ServiceDesc, srv)
+}
+
+func _TaskHandler_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).List(ctx, req.(*ListReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Get(ctx, req.(*GetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Create(ctx, req.(*CreateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Update(ctx, req.(*UpdateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).BatchCreate(ctx, req.(*BatchCreateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).BatchUpdate(ctx, req.(*BatchUpdateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_MoveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveTaskReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).MoveTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/MoveTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).MoveTask(ctx, req.(*MoveTaskReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_SkipOccurrence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SkipOccurrenceReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).SkipOccurrence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/SkipOccurrence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).SkipOccurrence(ctx, req.(*SkipOccurrenceReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_UpdateSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSeriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).UpdateSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/UpdateSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).UpdateSeries(ctx, req.(*UpdateSeriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_StopSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopSeriesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).StopSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/StopSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).StopSeries(ctx, req.(*StopSeriesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_AddReminder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddReminderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).AddReminder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/AddReminder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).AddReminder(ctx, req.(*AddReminderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_ListReminders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRemindersReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListReminders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListReminders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListReminders(ctx, req.(*ListRemindersReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_SnoozeReminder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnoozeReminderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).SnoozeReminder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/SnoozeReminder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).SnoozeReminder(ctx, req.(*SnoozeReminderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_DeleteReminder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteReminderReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).DeleteReminder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/DeleteReminder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).DeleteReminder(ctx, req.(*DeleteReminderReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/AddComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).AddComment(ctx, req.(*AddCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListComments(ctx, req.(*ListCommentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_EditComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).EditComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/EditComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).EditComment(ctx, req.(*EditCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCommentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).DeleteComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/DeleteComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).DeleteComment(ctx, req.(*DeleteCommentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_UploadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TaskHandlerServer).UploadAttachment(&taskHandlerUploadAttachmentServer{stream})
+}
+
+type TaskHandler_UploadAttachmentServer interface {
+	SendAndClose(*Attachment) error
+	Recv() (*UploadAttachmentReq, error)
+	grpc.ServerStream
+}
+
+type taskHandlerUploadAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskHandlerUploadAttachmentServer) SendAndClose(m *Attachment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *taskHandlerUploadAttachmentServer) Recv() (*UploadAttachmentReq, error) {
+	m := new(UploadAttachmentReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TaskHandler_DownloadAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadAttachmentReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskHandlerServer).DownloadAttachment(m, &taskHandlerDownloadAttachmentServer{stream})
+}
+
+type TaskHandler_DownloadAttachmentServer interface {
+	Send(*DownloadAttachmentRes) error
+	grpc.ServerStream
+}
+
+type taskHandlerDownloadAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskHandlerDownloadAttachmentServer) Send(m *DownloadAttachmentRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskHandler_ListAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAttachmentsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListAttachments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListAttachments(ctx, req.(*ListAttachmentsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_DeleteAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAttachmentReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).DeleteAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/DeleteAttachment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).DeleteAttachment(ctx, req.(*DeleteAttachmentReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_Share_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Share(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Share",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Share(ctx, req.(*ShareReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_Unshare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Unshare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Unshare",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Unshare(ctx, req.(*UnshareReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_ListCollaborators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCollaboratorsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListCollaborators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListCollaborators",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListCollaborators(ctx, req.(*ListCollaboratorsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_AddDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDependencyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).AddDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/AddDependency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).AddDependency(ctx, req.(*AddDependencyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_RemoveDependency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDependencyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).RemoveDependency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/RemoveDependency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).RemoveDependency(ctx, req.(*RemoveDependencyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_ListDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDependenciesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListDependencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListDependencies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListDependencies(ctx, req.(*ListDependenciesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_CreateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProjectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).CreateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/CreateProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).CreateProject(ctx, req.(*CreateProjectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_GetProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).GetProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/GetProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).GetProject(ctx, req.(*GetProjectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListProjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListProjects(ctx, req.(*ListProjectsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_UpdateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProjectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).UpdateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/UpdateProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).UpdateProject(ctx, req.(*UpdateProjectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_DeleteProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProjectReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).DeleteProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/DeleteProject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).DeleteProject(ctx, req.(*DeleteProjectReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_StartTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartTimerReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).StartTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/StartTimer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).StartTimer(ctx, req.(*StartTimerReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_StopTimer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopTimerReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).StopTimer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/StopTimer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).StopTimer(ctx, req.(*StopTimerReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskHandler_AddTimeEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTimeEntryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).AddTimeEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/AddTimeEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).AddTimeEntry(ctx, req.(*AddTimeEntryReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *taskHandlerClient) DeleteAll(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
-	out := new(emptypb.Empty)
-	err := c.cc.Invoke(ctx, "/api.task.TaskHandler/DeleteAll", in, out, opts...)
-	if err != nil {
+func _TaskHandler_TimeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeReportReq)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).TimeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/TimeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).TimeReport(ctx, req.(*TimeReportReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// TaskHandlerServer is the server API for TaskHandler service.
-// All implementations must embed UnimplementedTaskHandlerServer
-// for forward compatibility
-type TaskHandlerServer interface {
-	List(context.Context, *ListReq) (*ListTask, error)
-	Get(context.Context, *GetReq) (*Task, error)
-	Create(context.Context, *CreateReq) (*BasicTask, error)
-	Update(context.Context, *UpdateReq) (*BasicTask, error)
-	DeleteMultiple(context.Context, *DeleteMultipleReq) (*emptypb.Empty, error)
-	DeleteAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
-	mustEmbedUnimplementedTaskHandlerServer()
+func _TaskHandler_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/CreateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).CreateTemplate(ctx, req.(*CreateTemplateReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedTaskHandlerServer must be embedded to have forward compatible implementations.
-type UnimplementedTaskHandlerServer struct {
+func _TaskHandler_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).ListTemplates(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedTaskHandlerServer) List(context.Context, *ListReq) (*ListTask, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
-}
-func (UnimplementedTaskHandlerServer) Get(context.Context, *GetReq) (*Task, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
-}
-func (UnimplementedTaskHandlerServer) Create(context.Context, *CreateReq) (*BasicTask, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
-}
-func (UnimplementedTaskHandlerServer) Update(context.Context, *UpdateReq) (*BasicTask, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
-}
-func (UnimplementedTaskHandlerServer) DeleteMultiple(context.Context, *DeleteMultipleReq) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteMultiple not implemented")
-}
-func (UnimplementedTaskHandlerServer) DeleteAll(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteAll not implemented")
+func _TaskHandler_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/DeleteTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).DeleteTemplate(ctx, req.(*DeleteTemplateReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedTaskHandlerServer) mustEmbedUnimplementedTaskHandlerServer() {}
 
-// UnsafeTaskHandlerServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to TaskHandlerServer will
-// result in compilation errors.
-type UnsafeTaskHandlerServer interface {
-	mustEmbedUnimplementedTaskHandlerServer()
+func _TaskHandler_InstantiateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstantiateTemplateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).InstantiateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/InstantiateTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).InstantiateTemplate(ctx, req.(*InstantiateTemplateReq))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterTaskHandlerServer(s grpc.ServiceRegistrar, srv TaskHandlerServer) {
-	s.RegisterService(&TaskHandler_ServiceDesc, srv)
+func _TaskHandler_Undo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskHandlerServer).Undo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.task.TaskHandler/Undo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskHandlerServer).Undo(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _TaskHandler_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListReq)
+func _TaskHandler_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaskHandlerServer).List(ctx, in)
+		return srv.(TaskHandlerServer).GetHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.task.TaskHandler/List",
+		FullMethod: "/api.task.TaskHandler/GetHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskHandlerServer).List(ctx, req.(*ListReq))
+		return srv.(TaskHandlerServer).GetHistory(ctx, req.(*GetHistoryReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaskHandler_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetReq)
+func _TaskHandler_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskHandlerServer).Watch(m, &taskHandlerWatchServer{stream})
+}
+
+type TaskHandler_WatchServer interface {
+	Send(*WatchEvent) error
+	grpc.ServerStream
+}
+
+type taskHandlerWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskHandlerWatchServer) Send(m *WatchEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskHandler_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskHandlerServer).Export(m, &taskHandlerExportServer{stream})
+}
+
+type TaskHandler_ExportServer interface {
+	Send(*ExportChunk) error
+	grpc.ServerStream
+}
+
+type taskHandlerExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskHandlerExportServer) Send(m *ExportChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaskHandler_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TaskHandlerServer).Import(&taskHandlerImportServer{stream})
+}
+
+type TaskHandler_ImportServer interface {
+	SendAndClose(*ImportRes) error
+	Recv() (*ImportReq, error)
+	grpc.ServerStream
+}
+
+type taskHandlerImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *taskHandlerImportServer) SendAndClose(m *ImportRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *taskHandlerImportServer) Recv() (*ImportReq, error) {
+	m := new(ImportReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TaskHandler_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaskHandlerServer).Get(ctx, in)
+		return srv.(TaskHandlerServer).ListTrash(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.task.TaskHandler/Get",
+		FullMethod: "/api.task.TaskHandler/ListTrash",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskHandlerServer).Get(ctx, req.(*GetReq))
+		return srv.(TaskHandlerServer).ListTrash(ctx, req.(*ListTrashReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaskHandler_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateReq)
+func _TaskHandler_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaskHandlerServer).Create(ctx, in)
+		return srv.(TaskHandlerServer).Restore(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.task.TaskHandler/Create",
+		FullMethod: "/api.task.TaskHandler/Restore",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskHandlerServer).Create(ctx, req.(*CreateReq))
+		return srv.(TaskHandlerServer).Restore(ctx, req.(*RestoreReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaskHandler_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateReq)
+func _TaskHandler_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaskHandlerServer).Update(ctx, in)
+		return srv.(TaskHandlerServer).Purge(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/api.task.TaskHandler/Update",
+		FullMethod: "/api.task.TaskHandler/Purge",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskHandlerServer).Update(ctx, req.(*UpdateReq))
+		return srv.(TaskHandlerServer).Purge(ctx, req.(*PurgeReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -272,6 +1868,166 @@ var TaskHandler_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Update",
 			Handler:    _TaskHandler_Update_Handler,
 		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _TaskHandler_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _TaskHandler_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "MoveTask",
+			Handler:    _TaskHandler_MoveTask_Handler,
+		},
+		{
+			MethodName: "SkipOccurrence",
+			Handler:    _TaskHandler_SkipOccurrence_Handler,
+		},
+		{
+			MethodName: "UpdateSeries",
+			Handler:    _TaskHandler_UpdateSeries_Handler,
+		},
+		{
+			MethodName: "StopSeries",
+			Handler:    _TaskHandler_StopSeries_Handler,
+		},
+		{
+			MethodName: "AddReminder",
+			Handler:    _TaskHandler_AddReminder_Handler,
+		},
+		{
+			MethodName: "ListReminders",
+			Handler:    _TaskHandler_ListReminders_Handler,
+		},
+		{
+			MethodName: "SnoozeReminder",
+			Handler:    _TaskHandler_SnoozeReminder_Handler,
+		},
+		{
+			MethodName: "DeleteReminder",
+			Handler:    _TaskHandler_DeleteReminder_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _TaskHandler_AddComment_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _TaskHandler_ListComments_Handler,
+		},
+		{
+			MethodName: "EditComment",
+			Handler:    _TaskHandler_EditComment_Handler,
+		},
+		{
+			MethodName: "DeleteComment",
+			Handler:    _TaskHandler_DeleteComment_Handler,
+		},
+		{
+			MethodName: "ListAttachments",
+			Handler:    _TaskHandler_ListAttachments_Handler,
+		},
+		{
+			MethodName: "DeleteAttachment",
+			Handler:    _TaskHandler_DeleteAttachment_Handler,
+		},
+		{
+			MethodName: "Share",
+			Handler:    _TaskHandler_Share_Handler,
+		},
+		{
+			MethodName: "Unshare",
+			Handler:    _TaskHandler_Unshare_Handler,
+		},
+		{
+			MethodName: "ListCollaborators",
+			Handler:    _TaskHandler_ListCollaborators_Handler,
+		},
+		{
+			MethodName: "AddDependency",
+			Handler:    _TaskHandler_AddDependency_Handler,
+		},
+		{
+			MethodName: "RemoveDependency",
+			Handler:    _TaskHandler_RemoveDependency_Handler,
+		},
+		{
+			MethodName: "ListDependencies",
+			Handler:    _TaskHandler_ListDependencies_Handler,
+		},
+		{
+			MethodName: "CreateProject",
+			Handler:    _TaskHandler_CreateProject_Handler,
+		},
+		{
+			MethodName: "GetProject",
+			Handler:    _TaskHandler_GetProject_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _TaskHandler_ListProjects_Handler,
+		},
+		{
+			MethodName: "UpdateProject",
+			Handler:    _TaskHandler_UpdateProject_Handler,
+		},
+		{
+			MethodName: "DeleteProject",
+			Handler:    _TaskHandler_DeleteProject_Handler,
+		},
+		{
+			MethodName: "StartTimer",
+			Handler:    _TaskHandler_StartTimer_Handler,
+		},
+		{
+			MethodName: "StopTimer",
+			Handler:    _TaskHandler_StopTimer_Handler,
+		},
+		{
+			MethodName: "AddTimeEntry",
+			Handler:    _TaskHandler_AddTimeEntry_Handler,
+		},
+		{
+			MethodName: "TimeReport",
+			Handler:    _TaskHandler_TimeReport_Handler,
+		},
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _TaskHandler_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _TaskHandler_ListTemplates_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _TaskHandler_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "InstantiateTemplate",
+			Handler:    _TaskHandler_InstantiateTemplate_Handler,
+		},
+		{
+			MethodName: "Undo",
+			Handler:    _TaskHandler_Undo_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _TaskHandler_GetHistory_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _TaskHandler_ListTrash_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _TaskHandler_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _TaskHandler_Purge_Handler,
+		},
 		{
 			MethodName: "DeleteMultiple",
 			Handler:    _TaskHandler_DeleteMultiple_Handler,
@@ -281,6 +2037,32 @@ var TaskHandler_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _TaskHandler_DeleteAll_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAttachment",
+			Handler:       _TaskHandler_UploadAttachment_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadAttachment",
+			Handler:       _TaskHandler_DownloadAttachment_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _TaskHandler_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Export",
+			Handler:       _TaskHandler_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _TaskHandler_Import_Handler,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "app/task/api/task.proto",
 }
@@ -1,8 +1,74 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"regexp"
+	"time"
+	"todo-go-grpc/app/task/domain"
+)
+
+var colorRegexp = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+func (due *DueDate) Valid() error {
+	if due == nil {
+		return nil
+	}
+	if due.Time == nil {
+		return errors.New("Time of due date must not be empty")
+	}
+	if _, err := time.LoadLocation(due.Timezone); err != nil {
+		return errors.New("Timezone of due date is not valid")
+	}
+	return nil
+}
+
+func (priority Priority) Valid() error {
+	if _, ok := Priority_name[int32(priority)]; !ok {
+		return errors.New("Priority is not valid")
+	}
+	return nil
+}
+
+func (status Status) Valid() error {
+	if _, ok := Status_name[int32(status)]; !ok {
+		return errors.New("Status is not valid")
+	}
+	return nil
+}
+
+func (recurrence *Recurrence) Valid() error {
+	if recurrence == nil {
+		return nil
+	}
+	if _, err := domain.ParseRecurrence(recurrence.Rule); err != nil {
+		return err
+	}
+	if _, ok := RecurrenceAnchor_name[int32(recurrence.Anchor)]; !ok {
+		return errors.New("Anchor of recurrence is not valid")
+	}
+	return nil
+}
 
 func (req *ListReq) Valid() error {
+	for _, priority := range req.Priorities {
+		if err := priority.Valid(); err != nil {
+			return err
+		}
+	}
+	for _, status := range req.Statuses {
+		if err := status.Valid(); err != nil {
+			return err
+		}
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return errors.New("Timezone is not valid")
+	}
+	if req.DueFrom != nil && req.DueTo != nil && !req.DueFrom.AsTime().Before(req.DueTo.AsTime()) {
+		return errors.New("Due from must be before due to")
+	}
+	if _, ok := Ownership_name[int32(req.Ownership)]; !ok {
+		return errors.New("Ownership is not valid")
+	}
 	return nil
 }
 
@@ -17,7 +83,16 @@ func (req *CreateReq) Valid() error {
 	if req.Name == "" {
 		return errors.New("Name of task must not be empty")
 	}
-	return nil
+	if err := req.Priority.Valid(); err != nil {
+		return err
+	}
+	if err := req.Status.Valid(); err != nil {
+		return err
+	}
+	if err := req.Recurrence.Valid(); err != nil {
+		return err
+	}
+	return req.Due.Valid()
 }
 
 func (req *UpdateReq) Valid() error {
@@ -30,6 +105,354 @@ func (req *UpdateReq) Valid() error {
 	if req.NewTaskInfo.Name == "" {
 		return errors.New("Name of task must not be empty")
 	}
+	if err := req.NewTaskInfo.Priority.Valid(); err != nil {
+		return err
+	}
+	if err := req.NewTaskInfo.Status.Valid(); err != nil {
+		return err
+	}
+	return req.NewTaskInfo.Due.Valid()
+}
+
+func (req *MoveTaskReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	if req.Id == req.ParentId {
+		return errors.New("Task can not be parent of itself")
+	}
+	if req.Id == req.BeforeId || req.Id == req.AfterId {
+		return errors.New("Task can not be placed next to itself")
+	}
+	if req.BeforeId != 0 && req.BeforeId == req.AfterId {
+		return errors.New("Task before and task after must be different")
+	}
+	return nil
+}
+
+func (req *SkipOccurrenceReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *UpdateSeriesReq) Valid() error {
+	if req.SeriesId == 0 {
+		return errors.New("Series id must not be empty or zero")
+	}
+	if req.Recurrence == nil {
+		return errors.New("Recurrence must not be empty")
+	}
+	return req.Recurrence.Valid()
+}
+
+func (req *StopSeriesReq) Valid() error {
+	if req.SeriesId == 0 {
+		return errors.New("Series id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *AddReminderReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if (req.RemindTime == nil) == (req.BeforeDue == nil) {
+		return errors.New("Exactly one of remind time or before due must be set")
+	}
+	if req.BeforeDue != nil && req.BeforeDue.AsDuration() < 0 {
+		return errors.New("Before due must not be negative")
+	}
+	return nil
+}
+
+func (req *ListRemindersReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *SnoozeReminderReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	if req.Snooze == nil || req.Snooze.AsDuration() <= 0 {
+		return errors.New("Snooze must be a positive duration")
+	}
+	return nil
+}
+
+func (req *DeleteReminderReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *AddCommentReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.Body == "" {
+		return errors.New("Body of comment must not be empty")
+	}
+	return nil
+}
+
+func (req *ListCommentsReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *EditCommentReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	if req.Body == "" {
+		return errors.New("Body of comment must not be empty")
+	}
+	return nil
+}
+
+func (req *DeleteCommentReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (info *AttachmentInfo) Valid() error {
+	if info == nil {
+		return errors.New("First message must carry info of attachment")
+	}
+	if info.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if info.Name == "" {
+		return errors.New("Name of attachment must not be empty")
+	}
+	return nil
+}
+
+func (req *DownloadAttachmentReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *ListAttachmentsReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *DeleteAttachmentReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *ShareReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.UserId == 0 {
+		return errors.New("User id must not be empty or zero")
+	}
+	if req.Permission < Permission_VIEWER || req.Permission > Permission_OWNER {
+		return errors.New("Permission must be viewer, editor or owner")
+	}
+	return nil
+}
+
+func (req *UnshareReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.UserId == 0 {
+		return errors.New("User id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *ListCollaboratorsReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+// Color is empty or hex color like #1E90FF
+func validColor(color string) error {
+	if color == "" {
+		return nil
+	}
+	if !colorRegexp.MatchString(color) {
+		return errors.New("Color must be hex color like #1E90FF")
+	}
+	return nil
+}
+
+func (req *AddDependencyReq) Valid() error {
+	if req.TaskId == 0 || req.BlockerId == 0 {
+		return errors.New("Task id and blocker id must not be empty or zero")
+	}
+	if req.TaskId == req.BlockerId {
+		return errors.New("Task can not block itself")
+	}
+	return nil
+}
+
+func (req *RemoveDependencyReq) Valid() error {
+	if req.TaskId == 0 || req.BlockerId == 0 {
+		return errors.New("Task id and blocker id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *ListDependenciesReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *CreateProjectReq) Valid() error {
+	if req.Name == "" {
+		return errors.New("Name of project must not be empty")
+	}
+	return validColor(req.Color)
+}
+
+func (req *GetProjectReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *UpdateProjectReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	if req.NewProjectInfo == nil {
+		return errors.New("NewProjectInfo must not be empty")
+	}
+	if req.NewProjectInfo.Name == "" {
+		return errors.New("Name of project must not be empty")
+	}
+	return validColor(req.NewProjectInfo.Color)
+}
+
+func (req *DeleteProjectReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *StartTimerReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *StopTimerReq) Valid() error {
+	return nil
+}
+
+func (req *AddTimeEntryReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.StartTime == nil || req.EndTime == nil {
+		return errors.New("Start time and end time must not be empty")
+	}
+	if !req.StartTime.AsTime().Before(req.EndTime.AsTime()) {
+		return errors.New("Start time must be before end time")
+	}
+	return nil
+}
+
+func (req *TimeReportReq) Valid() error {
+	if req.From == nil || req.To == nil {
+		return errors.New("From and to must not be empty")
+	}
+	if !req.From.AsTime().Before(req.To.AsTime()) {
+		return errors.New("From must be before to")
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return errors.New("Timezone is not valid")
+	}
+	return nil
+}
+
+func (req *CreateTemplateReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.Name == "" {
+		return errors.New("Name of template must not be empty")
+	}
+	return nil
+}
+
+func (req *DeleteTemplateReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	return nil
+}
+
+func (req *InstantiateTemplateReq) Valid() error {
+	if req.Id == 0 {
+		return errors.New("Id must not be empty or zero")
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return errors.New("Timezone is not valid")
+	}
+	return nil
+}
+
+func (req *GetHistoryReq) Valid() error {
+	if req.TaskId == 0 {
+		return errors.New("Task id must not be empty or zero")
+	}
+	if req.PageSize < 0 || req.PageToken < 0 {
+		return errors.New("Page size and page token must not be negative")
+	}
+	return nil
+}
+
+func (req *ListTrashReq) Valid() error {
+	if req.PageSize < 0 || req.PageToken < 0 {
+		return errors.New("Page size and page token must not be negative")
+	}
+	return nil
+}
+
+func (req *RestoreReq) Valid() error {
+	if len(req.TasksId) == 0 {
+		return errors.New("Tasks id must not be empty")
+	}
+	return nil
+}
+
+func (req *PurgeReq) Valid() error {
+	if len(req.TasksId) == 0 && !req.All {
+		return errors.New("Tasks id must not be empty unless whole trash is purged")
+	}
+	if len(req.TasksId) != 0 && req.All {
+		return errors.New("Tasks id must be empty when whole trash is purged")
+	}
 	return nil
 }
 
@@ -39,3 +462,43 @@ func (req *DeleteMultipleReq) Valid() error {
 	}
 	return nil
 }
+
+func (req *BatchCreateReq) Valid() error {
+	if len(req.Items) == 0 {
+		return errors.New("Items must not be empty")
+	}
+	return nil
+}
+
+func (req *BatchUpdateReq) Valid() error {
+	if len(req.Items) == 0 {
+		return errors.New("Items must not be empty")
+	}
+	return nil
+}
+
+func (req *WatchReq) Valid() error {
+	if req.ResumeToken == "" {
+		return nil
+	}
+	_, err := domain.ParseHistoryCursor(req.ResumeToken)
+	return err
+}
+
+func (format FileFormat) Valid() error {
+	if _, ok := FileFormat_name[int32(format)]; !ok || format == FileFormat_FILE_FORMAT_UNSPECIFIED {
+		return errors.New("Format of file is not valid")
+	}
+	return nil
+}
+
+func (req *ExportReq) Valid() error {
+	return req.Format.Valid()
+}
+
+func (options *ImportOptions) Valid() error {
+	if options == nil {
+		return errors.New("First message must carry options of import")
+	}
+	return options.Format.Valid()
+}
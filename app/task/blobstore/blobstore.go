@@ -0,0 +1,18 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrBlobNotExists = errors.New("ErrBlobNotExists")
+)
+
+// BlobStore keeps bytes of attachments, metadata of attachments lives in database
+type BlobStore interface {
+	Put(ctx context.Context, key string, reader io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
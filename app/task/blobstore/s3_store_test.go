@@ -0,0 +1,223 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testBucket    = "attachments"
+	testRegion    = "eu-central-1"
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([^,]+), Signature=([0-9a-f]{64})$`)
+
+// Check request the way S3 does, from what arrives at server only
+func verifySignature(req *http.Request, secret_key string) error {
+	match := authorizationPattern.FindStringSubmatch(req.Header.Get("Authorization"))
+	if match == nil {
+		return errors.New("authorization header is malformed")
+	}
+	access_key, date, region, signed_headers, signature := match[1], match[2], match[3], match[4], match[5]
+	if access_key != testAccessKey || region != testRegion {
+		return errors.New("credential is not valid")
+	}
+	amz_date := req.Header.Get("x-amz-date")
+	if !strings.HasPrefix(amz_date, date) {
+		return errors.New("date of credential differs from x-amz-date")
+	}
+
+	canonical_headers := ""
+	for _, name := range strings.Split(signed_headers, ";") {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.Host
+		}
+		canonical_headers += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+	canonical_request := req.Method + "\n" + req.URL.EscapedPath() + "\n" + req.URL.RawQuery + "\n" +
+		canonical_headers + "\n" + signed_headers + "\n" + req.Header.Get("x-amz-content-sha256")
+	request_hash := sha256.Sum256([]byte(canonical_request))
+	string_to_sign := "AWS4-HMAC-SHA256\n" + amz_date + "\n" + date + "/" + region + "/s3/aws4_request\n" + hex.EncodeToString(request_hash[:])
+
+	key := []byte("AWS4" + secret_key)
+	for _, part := range []string{date, region, "s3", "aws4_request", string_to_sign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	if hex.EncodeToString(key) != signature {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+func TestSign(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 9, 30, 15, 0, time.UTC)
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		secret string
+		valid  bool
+	}{
+		{name: "put", method: http.MethodPut, url: "http://localhost:9000/attachments/ab/abcdef", secret: testSecretKey, valid: true},
+		{name: "get", method: http.MethodGet, url: "http://s3.example.com/attachments/key", secret: testSecretKey, valid: true},
+		{name: "escaped path", method: http.MethodDelete, url: "http://localhost:9000/attachments/a%20b", secret: testSecretKey, valid: true},
+		{name: "wrong secret", method: http.MethodGet, url: "http://localhost:9000/attachments/key", secret: "other", valid: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewS3Store("http://unused", testBucket, testRegion, testAccessKey, test.secret).(*s3Store)
+			req, err := http.NewRequest(test.method, test.url, nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error: %v", err)
+			}
+			store.sign(req, now)
+
+			if got := req.Header.Get("x-amz-date"); got != "20260301T093015Z" {
+				t.Fatalf("x-amz-date = %q, want 20260301T093015Z", got)
+			}
+			if got := req.Header.Get("x-amz-content-sha256"); got != unsignedPayload {
+				t.Fatalf("x-amz-content-sha256 = %q, want %q", got, unsignedPayload)
+			}
+			req.Host = req.URL.Host
+			if err := verifySignature(req, testSecretKey); (err == nil) != test.valid {
+				t.Fatalf("verifySignature() error = %v, want valid %v", err, test.valid)
+			}
+		})
+	}
+}
+
+// In-memory bucket which rejects requests not signed with the test secret
+func newTestS3Server(t *testing.T) *httptest.Server {
+	var mutex sync.Mutex
+	blobs := map[string][]byte{}
+	prefix := "/" + testBucket + "/"
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := verifySignature(r, testSecretKey); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.Error(w, "NoSuchBucket", http.StatusNotFound)
+			return
+		}
+		key := strings.TrimPrefix(r.URL.Path, prefix)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil || int64(len(data)) != r.ContentLength {
+				http.Error(w, "IncompleteBody", http.StatusBadRequest)
+				return
+			}
+			blobs[key] = data
+		case http.MethodGet:
+			data, ok := blobs[key]
+			if !ok {
+				http.Error(w, "NoSuchKey", http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		case http.MethodDelete:
+			delete(blobs, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestS3Store(t *testing.T) {
+	server := newTestS3Server(t)
+	defer server.Close()
+	store := NewS3Store(server.URL+"/", testBucket, testRegion, testAccessKey, testSecretKey)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		key  string
+		data string
+	}{
+		{name: "small", key: "ab/abcdef", data: "hello"},
+		{name: "empty", key: "cd/empty", data: ""},
+		{name: "large", key: "ef/large", data: strings.Repeat("0123456789", 100000)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := store.Put(ctx, test.key, strings.NewReader(test.data), int64(len(test.data))); err != nil {
+				t.Fatalf("Put() error: %v", err)
+			}
+
+			reader, err := store.Get(ctx, test.key)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			data, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil {
+				t.Fatalf("read blob error: %v", err)
+			}
+			if string(data) != test.data {
+				t.Fatalf("Get() returned %d bytes, want %d", len(data), len(test.data))
+			}
+
+			if err := store.Delete(ctx, test.key); err != nil {
+				t.Fatalf("Delete() error: %v", err)
+			}
+			if _, err := store.Get(ctx, test.key); !errors.Is(err, ErrBlobNotExists) {
+				t.Fatalf("Get() after delete error = %v, want %v", err, ErrBlobNotExists)
+			}
+		})
+	}
+}
+
+func TestS3StoreErrors(t *testing.T) {
+	server := newTestS3Server(t)
+	defer server.Close()
+	ctx := context.Background()
+
+	t.Run("delete missing blob", func(t *testing.T) {
+		store := NewS3Store(server.URL, testBucket, testRegion, testAccessKey, testSecretKey)
+		if err := store.Delete(ctx, "missing"); err != nil {
+			t.Fatalf("Delete() error: %v", err)
+		}
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		store := NewS3Store(server.URL, testBucket, testRegion, testAccessKey, "wrong")
+		err := store.Put(ctx, "key", strings.NewReader("data"), 4)
+		if err == nil || !strings.Contains(err.Error(), "403") {
+			t.Fatalf("Put() error = %v, want status 403", err)
+		}
+		if _, err := store.Get(ctx, "key"); err == nil || errors.Is(err, ErrBlobNotExists) {
+			t.Fatalf("Get() error = %v, want status 403", err)
+		}
+	})
+
+	t.Run("short body", func(t *testing.T) {
+		store := NewS3Store(server.URL, testBucket, testRegion, testAccessKey, testSecretKey)
+		if err := store.Put(ctx, "key", strings.NewReader("da"), 4); err == nil {
+			t.Fatal("Put() of body shorter than size succeeded")
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Metadata of file attached to task, bytes of file are kept in blob store under StorageKey
+type Attachment struct {
+	ID          int32     `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId      int32     `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task        Task      `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	UploaderId  int32     `json:"uploader_id" gorm:"column:uploader_id;not null;index"`
+	Name        string    `json:"name" gorm:"column:name;not null"`
+	Size        int64     `json:"size" gorm:"column:size;not null"`
+	ContentType string    `json:"content_type" gorm:"column:content_type"`
+	Sha256      string    `json:"sha256" gorm:"column:sha256;not null"`
+	StorageKey  string    `json:"-" gorm:"column:storage_key;not null;unique"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+}
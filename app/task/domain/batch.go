@@ -0,0 +1,34 @@
+package domain
+
+// Changes of one task in a batch update, same as arguments of a single update
+type TaskUpdate struct {
+	Id         int32
+	Info       *Task
+	TagsAdd    []int32
+	TagsRemove []int32
+	Force      bool
+}
+
+// Result of one item of a batch, Err is set when the item is not written
+type BatchResult struct {
+	Task *Task
+	Err  error
+}
+
+// Failed items keep their own error, the rest of an atomic batch is aborted along with them
+func AbortBatch(results []BatchResult) {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = BatchResult{Err: ErrBatchAborted}
+		}
+	}
+}
+
+func BatchFailed(results []BatchResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+	return false
+}
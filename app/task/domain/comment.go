@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	userDomain "todo-go-grpc/app/user/domain"
+
+	"gorm.io/gorm"
+)
+
+type Comment struct {
+	ID        int32           `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId    int32           `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task      Task            `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	AuthorId  int32           `json:"author_id" gorm:"column:author_id;not null"`
+	Author    userDomain.User `json:"author" gorm:"foreignKey:AuthorId"`
+	Body      string          `json:"body" gorm:"column:body;not null"`
+	CreatedAt time.Time       `json:"created_at" gorm:"column:created_at"`
+	EditedAt  *time.Time      `json:"edited_at" gorm:"column:edited_at"`
+	DeletedAt gorm.DeletedAt  `json:"-" gorm:"column:deleted_at;index"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Task is blocked by blocker until blocker is closed
+type Dependency struct {
+	ID        int32     `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId    int32     `json:"task_id" gorm:"column:task_id;not null;uniqueIndex:idx_dependencies_task_blocker"`
+	Task      Task      `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	BlockerId int32     `json:"blocker_id" gorm:"column:blocker_id;not null;uniqueIndex:idx_dependencies_task_blocker;index"`
+	Blocker   Task      `json:"-" gorm:"foreignKey:BlockerId;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+}
@@ -3,8 +3,40 @@ package domain
 import "errors"
 
 var (
-	ErrTaskNotExists = errors.New("ErrTaskNotExists")
-	ErrTaskExists    = errors.New("ErrTaskExists")
-	ErrTagNotExists  = errors.New("ErrTagNotExists")
-	ErrUserNotExists = errors.New("ErrUserNotExists")
+	ErrTaskNotExists          = errors.New("ErrTaskNotExists")
+	ErrTaskExists             = errors.New("ErrTaskExists")
+	ErrTagNotExists           = errors.New("ErrTagNotExists")
+	ErrUserNotExists          = errors.New("ErrUserNotExists")
+	ErrTaskVersionMismatch    = errors.New("ErrTaskVersionMismatch")
+	ErrParentNotExists        = errors.New("ErrParentNotExists")
+	ErrTaskCycle              = errors.New("ErrTaskCycle")
+	ErrTaskTooDeep            = errors.New("ErrTaskTooDeep")
+	ErrTaskHasOpenChildren    = errors.New("ErrTaskHasOpenChildren")
+	ErrRecurrenceInvalid      = errors.New("ErrRecurrenceInvalid")
+	ErrSeriesNotExists        = errors.New("ErrSeriesNotExists")
+	ErrTaskNotRecurring       = errors.New("ErrTaskNotRecurring")
+	ErrReminderNotExists      = errors.New("ErrReminderNotExists")
+	ErrCommentNotExists       = errors.New("ErrCommentNotExists")
+	ErrPermissionDenied       = errors.New("ErrPermissionDenied")
+	ErrAttachmentNotExists    = errors.New("ErrAttachmentNotExists")
+	ErrAttachmentTooLarge     = errors.New("ErrAttachmentTooLarge")
+	ErrAttachmentQuota        = errors.New("ErrAttachmentQuota")
+	ErrShareNotExists         = errors.New("ErrShareNotExists")
+	ErrProjectNotExists       = errors.New("ErrProjectNotExists")
+	ErrProjectVersionMismatch = errors.New("ErrProjectVersionMismatch")
+	ErrStatusTransition       = errors.New("ErrStatusTransition")
+	ErrDependencyExists       = errors.New("ErrDependencyExists")
+	ErrDependencyNotExists    = errors.New("ErrDependencyNotExists")
+	ErrDependencyCycle        = errors.New("ErrDependencyCycle")
+	ErrTaskBlocked            = errors.New("ErrTaskBlocked")
+	ErrTimerRunning           = errors.New("ErrTimerRunning")
+	ErrTimerNotRunning        = errors.New("ErrTimerNotRunning")
+	ErrRankNeighbour          = errors.New("ErrRankNeighbour")
+	ErrTemplateNotExists      = errors.New("ErrTemplateNotExists")
+	ErrTemplateExists         = errors.New("ErrTemplateExists")
+	ErrNothingToUndo          = errors.New("ErrNothingToUndo")
+	ErrUndoConflict           = errors.New("ErrUndoConflict")
+	ErrBatchAborted           = errors.New("ErrBatchAborted")
+	ErrRecordInvalid          = errors.New("ErrRecordInvalid")
+	ErrImportDryRun           = errors.New("ErrImportDryRun")
 )
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	tagDomain "todo-go-grpc/app/tag/domain"
+)
+
+// Task as one record of export file, it refers to its parent by external id and to its tags by value
+type TaskRecord struct {
+	ExternalId       string     `json:"external_id"`
+	Name             string     `json:"name"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Priority         string     `json:"priority"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	DueAllDay        bool       `json:"due_all_day"`
+	DueTimezone      string     `json:"due_timezone,omitempty"`
+	ParentExternalId string     `json:"parent_external_id,omitempty"`
+	Tags             []string   `json:"tags"`
+	CreatedAt        time.Time  `json:"created_at"`
+	DoneAt           *time.Time `json:"done_at,omitempty"`
+}
+
+// Parent of task is given separately, it is not known from task itself
+func NewTaskRecord(task *Task, parent_external_id string) TaskRecord {
+	record := TaskRecord{
+		Name:             task.Name,
+		Description:      task.Description,
+		Status:           task.Status.String(),
+		Priority:         task.Priority.Name(),
+		DueAt:            task.DueAt,
+		DueAllDay:        task.DueAllDay,
+		DueTimezone:      task.DueTimezone,
+		ParentExternalId: parent_external_id,
+		Tags:             []string{},
+		CreatedAt:        task.CreatedAt,
+	}
+	if task.ExternalId != nil {
+		record.ExternalId = *task.ExternalId
+	}
+	for _, tag := range task.Tags {
+		record.Tags = append(record.Tags, tag.Value)
+	}
+	if task.IsDone {
+		done_at := task.DoneAt
+		record.DoneAt = &done_at
+	}
+	return record
+}
+
+// Get task of record without parent and tags, they are resolved by repository
+func (r *TaskRecord) Task() (*Task, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrRecordInvalid)
+	}
+
+	task := &Task{
+		Name:        r.Name,
+		Description: r.Description,
+		DueAt:       r.DueAt,
+		DueAllDay:   r.DueAllDay,
+		DueTimezone: r.DueTimezone,
+		CreatedAt:   r.CreatedAt,
+		Tags:        []tagDomain.Tag{},
+	}
+	if r.DoneAt != nil {
+		task.DoneAt = *r.DoneAt
+	}
+	if r.ExternalId != "" {
+		external_id := r.ExternalId
+		task.ExternalId = &external_id
+	}
+	if r.Status != "" {
+		status, err := ParseStatus(r.Status)
+		if err != nil {
+			return nil, err
+		}
+		task.Status = status
+	}
+	if r.Priority != "" {
+		priority, err := ParsePriority(r.Priority)
+		if err != nil {
+			return nil, err
+		}
+		task.Priority = priority
+	}
+	if r.DueTimezone != "" {
+		if _, err := time.LoadLocation(r.DueTimezone); err != nil {
+			return nil, fmt.Errorf("%w: timezone %q is not valid", ErrRecordInvalid, r.DueTimezone)
+		}
+	}
+	return task, nil
+}
+
+type ImportStatus string
+
+const (
+	ImportCreated ImportStatus = "CREATED"
+	// Task with the same external id already exists or comes earlier in the same import
+	ImportSkipped ImportStatus = "SKIPPED"
+	ImportFailed  ImportStatus = "FAILED"
+)
+
+// Row is the number of record in file, starting from 1
+type ImportResult struct {
+	Row        int
+	ExternalId string
+	Status     ImportStatus
+	TaskId     int32
+	Err        error
+}
+
+type ImportOptions struct {
+	// Everything is checked and rolled back at the end
+	DryRun bool
+	// Tags which do not exist are created, otherwise their records fail
+	CreateMissingTags bool
+}
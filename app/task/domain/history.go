@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type HistoryAction string
+
+const (
+	HistoryCreated  HistoryAction = "CREATED"
+	HistoryUpdated  HistoryAction = "UPDATED"
+	HistoryMoved    HistoryAction = "MOVED"
+	HistoryDeleted  HistoryAction = "DELETED"
+	HistoryRestored HistoryAction = "RESTORED"
+	// Only entry left of task purged from trash
+	HistoryPurged HistoryAction = "PURGED"
+)
+
+// Value of field before and after change, nil when field had no value
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Changed fields of task by their name, stored as JSON
+type FieldChanges map[string]FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+func (c *FieldChanges) Scan(value any) error {
+	return scanJSON(value, c)
+}
+
+// Decode JSON column into target, NULL leaves target empty
+func scanJSON(value any, target any) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, target)
+	case string:
+		return json.Unmarshal([]byte(data), target)
+	case nil:
+		return nil
+	}
+	return errors.New("JSON column must be scanned from bytes or string")
+}
+
+// One change of task, written in the same transaction as the change itself.
+// Entry has no foreign key on task, so tombstone of purged task outlives it
+type HistoryEntry struct {
+	ID        int32         `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId    int32         `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task      Task          `json:"-" gorm:"foreignKey:TaskId;constraint:-"`
+	ActorId   int32         `json:"actor_id" gorm:"column:actor_id;not null"`
+	Action    HistoryAction `json:"action" gorm:"column:action;not null"`
+	Changes   FieldChanges  `json:"changes" gorm:"column:changes;type:jsonb;not null"`
+	CreatedAt time.Time     `json:"created_at" gorm:"column:created_at;not null;index"`
+	// Id of transaction which wrote entry, watchers read entries in its order
+	TxId int64 `json:"-" gorm:"column:tx_id;not null;default:txid_current();index"`
+}
+
+// Position of watcher in history. Ids are taken before commit, so entry of a transaction which commits late
+// can get an id lower than the one a watcher is already past. Entries are read in order of transaction id instead
+// and only when every transaction before them has ended, so none is skipped
+type HistoryCursor struct {
+	TxId int64
+	Id   int32
+}
+
+func (c HistoryCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.TxId, c.Id)
+}
+
+func ParseHistoryCursor(token string) (HistoryCursor, error) {
+	tx_text, id_text, found := strings.Cut(token, "-")
+	if !found {
+		return HistoryCursor{}, errors.New("Resume token is not valid")
+	}
+	tx_id, tx_err := strconv.ParseInt(tx_text, 10, 64)
+	id, id_err := strconv.ParseInt(id_text, 10, 32)
+	if tx_err != nil || id_err != nil || tx_id < 0 || id < 0 {
+		return HistoryCursor{}, errors.New("Resume token is not valid")
+	}
+	return HistoryCursor{TxId: tx_id, Id: int32(id)}, nil
+}
+
+// Get fields which differ between two states of task, before is nil for a new task.
+// Tags are compared only when both states have them loaded
+func DiffTasks(before *Task, after *Task) FieldChanges {
+	created := before == nil
+	if created {
+		before = &Task{}
+	}
+	optionalId := func(id *int32) any {
+		if id == nil {
+			return nil
+		}
+		return *id
+	}
+	optionalTime := func(at *time.Time) any {
+		if at == nil {
+			return nil
+		}
+		return at.UTC().Format(time.RFC3339)
+	}
+	statusName := func(status Status) any {
+		if status == StatusUnspecified {
+			return nil
+		}
+		return status.String()
+	}
+	tagIds := func(task *Task) []int32 {
+		ids := []int32{}
+		for _, tag := range task.Tags {
+			ids = append(ids, tag.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+
+	changes := FieldChanges{}
+	compare := func(field string, before any, after any) {
+		before_json, _ := json.Marshal(before)
+		after_json, _ := json.Marshal(after)
+		if string(before_json) != string(after_json) {
+			changes[field] = FieldChange{Before: before, After: after}
+		}
+	}
+
+	compare("name", before.Name, after.Name)
+	compare("description", before.Description, after.Description)
+	compare("creator_id", before.CreatorId, after.CreatorId)
+	compare("due_at", optionalTime(before.DueAt), optionalTime(after.DueAt))
+	compare("due_all_day", before.DueAllDay, after.DueAllDay)
+	compare("priority", before.Priority, after.Priority)
+	compare("parent_id", optionalId(before.ParentId), optionalId(after.ParentId))
+	compare("assignee_id", optionalId(before.AssigneeId), optionalId(after.AssigneeId))
+	compare("project_id", optionalId(before.ProjectId), optionalId(after.ProjectId))
+	compare("status", statusName(before.Status), statusName(after.Status))
+	if (created || before.Tags != nil) && after.Tags != nil {
+		compare("tags", tagIds(before), tagIds(after))
+	}
+
+	return changes
+}
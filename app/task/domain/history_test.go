@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	tagDomain "todo-go-grpc/app/tag/domain"
+)
+
+func TestDiffTasks(t *testing.T) {
+	id := func(value int32) *int32 { return &value }
+	due := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+	tags := func(ids ...int32) []tagDomain.Tag {
+		result := []tagDomain.Tag{}
+		for _, tag_id := range ids {
+			result = append(result, tagDomain.Tag{ID: tag_id})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		before *Task
+		after  *Task
+		want   FieldChanges
+	}{
+		{
+			name:   "nothing changed",
+			before: &Task{Name: "a", Status: StatusTodo, ParentId: id(1)},
+			after:  &Task{Name: "a", Status: StatusTodo, ParentId: id(1)},
+			want:   FieldChanges{},
+		},
+		{
+			name:   "created",
+			before: nil,
+			after:  &Task{Name: "a", CreatorId: 2, Status: StatusTodo, Tags: tags(3)},
+			want: FieldChanges{
+				"name":       {Before: "", After: "a"},
+				"creator_id": {Before: int32(0), After: int32(2)},
+				"status":     {Before: nil, After: "TODO"},
+				"tags":       {Before: []int32{}, After: []int32{3}},
+			},
+		},
+		{
+			name:   "due time in UTC",
+			before: &Task{},
+			after:  &Task{DueAt: &due},
+			want:   FieldChanges{"due_at": {Before: nil, After: "2026-03-01T07:00:00Z"}},
+		},
+		{
+			name:   "optional ids",
+			before: &Task{ParentId: id(1), AssigneeId: nil},
+			after:  &Task{ParentId: nil, AssigneeId: id(4)},
+			want: FieldChanges{
+				"parent_id":   {Before: int32(1), After: nil},
+				"assignee_id": {Before: nil, After: int32(4)},
+			},
+		},
+		{
+			name:   "status",
+			before: &Task{Status: StatusTodo},
+			after:  &Task{Status: StatusDone},
+			want:   FieldChanges{"status": {Before: "TODO", After: "DONE"}},
+		},
+		{
+			name:   "tags in any order",
+			before: &Task{Tags: tags(2, 1)},
+			after:  &Task{Tags: tags(1, 2, 3)},
+			want:   FieldChanges{"tags": {Before: []int32{1, 2}, After: []int32{1, 2, 3}}},
+		},
+		{
+			name:   "tags not loaded before",
+			before: &Task{},
+			after:  &Task{Tags: tags(1)},
+			want:   FieldChanges{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DiffTasks(test.before, test.after); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("DiffTasks() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseHistoryCursor(t *testing.T) {
+	tests := []struct {
+		token   string
+		want    HistoryCursor
+		invalid bool
+	}{
+		{token: "0-0", want: HistoryCursor{}},
+		{token: "1234-56", want: HistoryCursor{TxId: 1234, Id: 56}},
+		{token: HistoryCursor{TxId: 9007199254740993, Id: 2147483647}.String(), want: HistoryCursor{TxId: 9007199254740993, Id: 2147483647}},
+		{token: "", invalid: true},
+		{token: "1234", invalid: true},
+		{token: "a-1", invalid: true},
+		{token: "1-2-3", invalid: true},
+		{token: "-1-2", invalid: true},
+		{token: "1-2147483648", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.token, func(t *testing.T) {
+			cursor, err := ParseHistoryCursor(test.token)
+			if test.invalid {
+				if err == nil {
+					t.Fatalf("ParseHistoryCursor(%q) = %v, want error", test.token, cursor)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHistoryCursor(%q) error: %v", test.token, err)
+			}
+			if cursor != test.want {
+				t.Fatalf("ParseHistoryCursor(%q) = %v, want %v", test.token, cursor, test.want)
+			}
+		})
+	}
+}
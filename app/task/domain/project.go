@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Project groups tasks into a list, task belongs to at most one project
+type Project struct {
+	ID          int32     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"column:name;not null"`
+	Description string    `json:"description" gorm:"column:description"`
+	Color       string    `json:"color" gorm:"column:color"`
+	Archived    bool      `json:"archived" gorm:"column:archived;not null;default:false"`
+	OwnerId     int32     `json:"owner_id" gorm:"column:owner_id;not null;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+	Version     int32     `json:"version" gorm:"column:version;not null;default:1"`
+	OpenCount   int32     `json:"open_count" gorm:"->;-:migration"`
+	DoneCount   int32     `json:"done_count" gorm:"->;-:migration"`
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Rank is a string of base 36 digits, ranks are compared byte by byte.
+// Task is placed between two others by taking a rank between theirs,
+// so no other task has to be changed
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Rank can not be longer, list has to be rebalanced when no rank of this length fits
+const MaxRankLength = 24
+
+// Get rank between prev and next, empty prev is the start and empty next is the end of list.
+// False is returned when prev is not before next or no rank fits in MaxRankLength
+func RankBetween(prev string, next string) (string, bool) {
+	if next != "" && prev >= next {
+		return "", false
+	}
+
+	rank := []byte{}
+	// Rank stays below next until one of its digits is less than digit of next
+	bounded := next != ""
+	for i := 0; i < MaxRankLength; i++ {
+		low := 0
+		if i < len(prev) {
+			low = strings.IndexByte(rankDigits, prev[i])
+		}
+		high := len(rankDigits)
+		if bounded {
+			// Every rank which starts with next is after it
+			if i >= len(next) {
+				return "", false
+			}
+			high = strings.IndexByte(rankDigits, next[i])
+		}
+
+		if high-low > 1 {
+			return string(append(rank, rankDigits[(low+high)/2])), true
+		}
+		rank = append(rank, rankDigits[low])
+		if low < high {
+			bounded = false
+		}
+	}
+
+	return "", false
+}
+
+// Get count evenly spaced ranks of the same length, used to rebalance a list
+func SpreadRanks(count int) []string {
+	base := int64(len(rankDigits))
+	// Leave at least one digit of space between ranks
+	width, space := 1, base
+	for space < int64(count+1)*base {
+		width++
+		space *= base
+	}
+
+	step := space / int64(count+1)
+	ranks := make([]string, count)
+	for i := range ranks {
+		value := step * int64(i+1)
+		rank := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			rank[j] = rankDigits[value%base]
+			value /= base
+		}
+		ranks[i] = string(rank)
+	}
+
+	return ranks
+}
+
+// Get count evenly spaced ranks after prev, used to append many tasks to the end of list at once.
+// Ranks are as short as possible while leaving at least one digit of space between them.
+// False is returned when they do not fit in MaxRankLength
+func RanksAfter(prev string, count int) ([]string, bool) {
+	base := big.NewInt(int64(len(rankDigits)))
+	// Ranks of one width are compared as numbers, prev is padded with zeros which keeps its order
+	value := new(big.Int)
+	for _, digit := range []byte(prev) {
+		value.Mul(value, base).Add(value, big.NewInt(int64(strings.IndexByte(rankDigits, digit))))
+	}
+	space := new(big.Int).Exp(base, big.NewInt(int64(len(prev))), nil)
+	need := new(big.Int).Mul(big.NewInt(int64(count+1)), base)
+
+	for width := len(prev); width <= MaxRankLength; width++ {
+		if width > len(prev) {
+			value.Mul(value, base)
+			space.Mul(space, base)
+		}
+		gap := new(big.Int).Sub(space, value)
+		if width == 0 || gap.Cmp(need) < 0 {
+			continue
+		}
+
+		step := gap.Div(gap, big.NewInt(int64(count+1)))
+		ranks := make([]string, count)
+		current := new(big.Int).Set(value)
+		digit := new(big.Int)
+		for i := range ranks {
+			current.Add(current, step)
+			rank := make([]byte, width)
+			rest := new(big.Int).Set(current)
+			for j := width - 1; j >= 0; j-- {
+				rest.DivMod(rest, base, digit)
+				rank[j] = rankDigits[digit.Int64()]
+			}
+			ranks[i] = string(rank)
+		}
+		return ranks, true
+	}
+
+	return nil, false
+}
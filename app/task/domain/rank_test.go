@@ -0,0 +1,179 @@
+package domain
+
+import "testing"
+
+// Ranks must be strictly increasing and fit in MaxRankLength
+func checkRanks(t *testing.T, prev string, ranks []string) {
+	t.Helper()
+	for i, rank := range ranks {
+		if rank <= prev {
+			t.Fatalf("rank %d %q is not after %q", i, rank, prev)
+		}
+		if len(rank) > MaxRankLength {
+			t.Fatalf("rank %d %q is longer than %d", i, rank, MaxRankLength)
+		}
+		prev = rank
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+		want string
+	}{
+		{name: "empty list", prev: "", next: "", want: "i"},
+		{name: "before first", prev: "", next: "1", want: "0i"},
+		{name: "after last", prev: "z", next: "", want: "zi"},
+		{name: "adjacent digits", prev: "a", next: "b", want: "ai"},
+		{name: "adjacent longer ranks", prev: "a1", next: "a2", want: "a1i"},
+		{name: "prev longer than next", prev: "az", next: "b", want: "azi"},
+		{name: "next longer than prev", prev: "i", next: "i0i", want: "i09"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rank, ok := RankBetween(test.prev, test.next)
+			if !ok {
+				t.Fatalf("RankBetween(%q, %q) does not fit", test.prev, test.next)
+			}
+			if rank != test.want {
+				t.Fatalf("RankBetween(%q, %q) = %q, want %q", test.prev, test.next, rank, test.want)
+			}
+			if rank <= test.prev || (test.next != "" && rank >= test.next) {
+				t.Fatalf("RankBetween(%q, %q) = %q is not between them", test.prev, test.next, rank)
+			}
+		})
+	}
+}
+
+func TestRankBetweenNoSpace(t *testing.T) {
+	full := ""
+	for len(full) < MaxRankLength {
+		full += "a"
+	}
+	tests := []struct {
+		name string
+		prev string
+		next string
+	}{
+		{name: "same rank", prev: "a", next: "a"},
+		{name: "prev after next", prev: "b", next: "a"},
+		{name: "next is prev with zero", prev: "a", next: "a0"},
+		{name: "longest rank", prev: full, next: full + "1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rank, ok := RankBetween(test.prev, test.next); ok {
+				t.Fatalf("RankBetween(%q, %q) = %q, want no space", test.prev, test.next, rank)
+			}
+		})
+	}
+}
+
+// Inserting again and again at the same place shortens space, until it runs out at MaxRankLength
+func TestRankBetweenRepeated(t *testing.T) {
+	prev, next := "a", "b"
+	for i := 0; ; i++ {
+		rank, ok := RankBetween(prev, next)
+		if !ok {
+			if i < 50 {
+				t.Fatalf("no space after %d inserts", i)
+			}
+			return
+		}
+		if rank <= prev || rank >= next {
+			t.Fatalf("RankBetween(%q, %q) = %q is not between them", prev, next, rank)
+		}
+		if len(rank) > MaxRankLength {
+			t.Fatalf("rank %q is longer than %d", rank, MaxRankLength)
+		}
+		next = rank
+	}
+}
+
+func TestSpreadRanks(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		width int
+	}{
+		{name: "one", count: 1, width: 2},
+		{name: "fits two digits", count: 35, width: 2},
+		{name: "needs three digits", count: 36, width: 3},
+		{name: "many", count: 10000, width: 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranks := SpreadRanks(test.count)
+			if len(ranks) != test.count {
+				t.Fatalf("SpreadRanks(%d) returned %d ranks", test.count, len(ranks))
+			}
+			for _, rank := range ranks {
+				if len(rank) != test.width {
+					t.Fatalf("rank %q is not %d digits long", rank, test.width)
+				}
+			}
+			checkRanks(t, "", ranks)
+			// Another task fits between every two ranks
+			for i := 1; i < len(ranks); i++ {
+				if _, ok := RankBetween(ranks[i-1], ranks[i]); !ok {
+					t.Fatalf("no rank fits between %q and %q", ranks[i-1], ranks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRanksAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		prev  string
+		count int
+	}{
+		{name: "empty list", prev: "", count: 500},
+		{name: "after one rank", prev: "i", count: 500},
+		{name: "after rebalanced list", prev: SpreadRanks(140)[139], count: 500},
+		{name: "near the end", prev: "zzzzzz", count: 500},
+		{name: "one rank", prev: "a", count: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranks, ok := RanksAfter(test.prev, test.count)
+			if !ok {
+				t.Fatalf("RanksAfter(%q, %d) does not fit", test.prev, test.count)
+			}
+			if len(ranks) != test.count {
+				t.Fatalf("RanksAfter() returned %d ranks, want %d", len(ranks), test.count)
+			}
+			checkRanks(t, test.prev, ranks)
+		})
+	}
+}
+
+// Each batch of 500 goes after the previous one, as BatchCreate into one list does, until the list is rebalanced
+func TestRanksAfterRepeated(t *testing.T) {
+	prev := ""
+	for batch := 0; batch < 10; batch++ {
+		ranks, ok := RanksAfter(prev, 500)
+		if !ok {
+			t.Fatalf("batch %d after %q does not fit", batch, prev)
+		}
+		checkRanks(t, prev, ranks)
+		prev = ranks[len(ranks)-1]
+	}
+}
+
+func TestRanksAfterNoSpace(t *testing.T) {
+	prev := ""
+	for len(prev) < MaxRankLength {
+		prev += "z"
+	}
+	if ranks, ok := RanksAfter(prev, 1); ok {
+		t.Fatalf("RanksAfter(%q, 1) = %v, want no space", prev, ranks)
+	}
+}
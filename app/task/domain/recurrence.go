@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+const untilLayout = "20060102T150405Z"
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Subset of RFC 5545 RRULE with FREQ, INTERVAL, BYDAY for weekly rule,
+// BYMONTHDAY for monthly rule, UNTIL and COUNT
+type Recurrence struct {
+	Frequency Frequency
+	Interval  int
+	Weekdays  []time.Weekday
+	// Day of month, negative value counts from the end of month
+	MonthDay int
+	Until    *time.Time
+	Count    int32
+}
+
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	recurrence := &Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not KEY=VALUE", ErrRecurrenceInvalid, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			recurrence.Frequency = Frequency(strings.ToUpper(value))
+			if recurrence.Frequency != FrequencyDaily && recurrence.Frequency != FrequencyWeekly && recurrence.Frequency != FrequencyMonthly {
+				return nil, fmt.Errorf("%w: frequency %q is not supported", ErrRecurrenceInvalid, value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("%w: interval must be a positive number", ErrRecurrenceInvalid)
+			}
+			recurrence.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(value), ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%w: weekday %q is not valid", ErrRecurrenceInvalid, day)
+				}
+				recurrence.Weekdays = append(recurrence.Weekdays, weekday)
+			}
+		case "BYMONTHDAY":
+			month_day, err := strconv.Atoi(value)
+			if err != nil || month_day == 0 || month_day < -31 || month_day > 31 {
+				return nil, fmt.Errorf("%w: day of month must be from 1 to 31 or from -31 to -1", ErrRecurrenceInvalid)
+			}
+			recurrence.MonthDay = month_day
+		case "UNTIL":
+			until, err := time.Parse(untilLayout, value)
+			if err != nil {
+				if until, err = time.Parse("20060102", value); err != nil {
+					return nil, fmt.Errorf("%w: until %q is not a date or UTC date-time", ErrRecurrenceInvalid, value)
+				}
+				// Date only until includes the whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			recurrence.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("%w: count must be a positive number", ErrRecurrenceInvalid)
+			}
+			recurrence.Count = int32(count)
+		default:
+			return nil, fmt.Errorf("%w: %q is not supported", ErrRecurrenceInvalid, key)
+		}
+	}
+
+	if recurrence.Frequency == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrRecurrenceInvalid)
+	}
+	if len(recurrence.Weekdays) != 0 && recurrence.Frequency != FrequencyWeekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported by weekly rule", ErrRecurrenceInvalid)
+	}
+	if recurrence.MonthDay != 0 && recurrence.Frequency != FrequencyMonthly {
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported by monthly rule", ErrRecurrenceInvalid)
+	}
+	if recurrence.Until != nil && recurrence.Count != 0 {
+		return nil, fmt.Errorf("%w: UNTIL and COUNT must not be used together", ErrRecurrenceInvalid)
+	}
+
+	return recurrence, nil
+}
+
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + string(r.Frequency)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) != 0 {
+		days := []string{}
+		for _, weekday := range r.Weekdays {
+			for name, day := range weekdays {
+				if day == weekday {
+					days = append(days, name)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if r.Count != 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(int(r.Count)))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Get first occurrence after base, time of day of base is kept
+func (r *Recurrence) Next(base time.Time) time.Time {
+	switch r.Frequency {
+	case FrequencyWeekly:
+		if len(r.Weekdays) == 0 {
+			return base.AddDate(0, 0, 7*r.Interval)
+		}
+
+		base_week := startOfWeek(base)
+		for day := 1; day <= 7*(r.Interval+1); day++ {
+			candidate := base.AddDate(0, 0, day)
+			weeks := daysBetween(base_week, startOfWeek(candidate)) / 7
+			if weeks%r.Interval == 0 && r.hasWeekday(candidate.Weekday()) {
+				return candidate
+			}
+		}
+	case FrequencyMonthly:
+		for months := 0; months <= r.Interval; months += r.Interval {
+			candidate := r.dayOfMonth(base, months)
+			if candidate.After(base) {
+				return candidate
+			}
+		}
+	}
+
+	return base.AddDate(0, 0, r.Interval)
+}
+
+// Check no occurrence is left, occurrences is number of tasks already created in series
+func (r *Recurrence) Ended(next time.Time, occurrences int32) bool {
+	if r.Until != nil && next.After(*r.Until) {
+		return true
+	}
+	return r.Count != 0 && occurrences >= r.Count
+}
+
+func (r *Recurrence) hasWeekday(weekday time.Weekday) bool {
+	for _, day := range r.Weekdays {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Day which does not exist in month, for example 31 in April, falls back to the last day of month
+func (r *Recurrence) dayOfMonth(base time.Time, months int) time.Time {
+	first := time.Date(base.Year(), base.Month()+time.Month(months), 1, base.Hour(), base.Minute(), base.Second(), base.Nanosecond(), base.Location())
+	days_in_month := first.AddDate(0, 1, -1).Day()
+
+	day := r.MonthDay
+	if day == 0 {
+		day = base.Day()
+	} else if day < 0 {
+		day = days_in_month + day + 1
+	}
+	if day < 1 {
+		day = 1
+	}
+	if day > days_in_month {
+		day = days_in_month
+	}
+
+	return first.AddDate(0, 0, day-1)
+}
+
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	from_date := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to_date := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to_date.Sub(from_date).Hours() / 24)
+}
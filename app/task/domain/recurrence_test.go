@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		want string
+	}{
+		{name: "daily", rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and count", rule: "RRULE:FREQ=DAILY;COUNT=3", want: "FREQ=DAILY;COUNT=3"},
+		{name: "lower case", rule: "freq=monthly;bymonthday=-1", want: "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{name: "weekly days", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{name: "interval one is default", rule: "FREQ=WEEKLY;INTERVAL=1", want: "FREQ=WEEKLY"},
+		{name: "until date includes whole day", rule: "FREQ=DAILY;UNTIL=20260131", want: "FREQ=DAILY;UNTIL=20260131T235959Z"},
+		{name: "until date-time", rule: "FREQ=DAILY;UNTIL=20260131T120000Z", want: "FREQ=DAILY;UNTIL=20260131T120000Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(test.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error: %v", test.rule, err)
+			}
+			if got := recurrence.String(); got != test.want {
+				t.Fatalf("ParseRecurrence(%q).String() = %q, want %q", test.rule, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{name: "empty", rule: ""},
+		{name: "no value", rule: "FREQ"},
+		{name: "no frequency", rule: "INTERVAL=2"},
+		{name: "yearly", rule: "FREQ=YEARLY"},
+		{name: "zero interval", rule: "FREQ=DAILY;INTERVAL=0"},
+		{name: "unknown weekday", rule: "FREQ=WEEKLY;BYDAY=XX"},
+		{name: "weekday of daily rule", rule: "FREQ=DAILY;BYDAY=MO"},
+		{name: "day of month of weekly rule", rule: "FREQ=WEEKLY;BYMONTHDAY=1"},
+		{name: "day of month out of range", rule: "FREQ=MONTHLY;BYMONTHDAY=32"},
+		{name: "until and count", rule: "FREQ=DAILY;COUNT=2;UNTIL=20260101"},
+		{name: "bad until", rule: "FREQ=DAILY;UNTIL=tomorrow"},
+		{name: "unsupported part", rule: "FREQ=DAILY;BYHOUR=9"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseRecurrence(test.rule); !errors.Is(err, ErrRecurrenceInvalid) {
+				t.Fatalf("ParseRecurrence(%q) error = %v, want %v", test.rule, err, ErrRecurrenceInvalid)
+			}
+		})
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 9, 30, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		rule string
+		base time.Time
+		want time.Time
+	}{
+		{name: "daily", rule: "FREQ=DAILY", base: at(time.January, 5), want: at(time.January, 6)},
+		{name: "every third day", rule: "FREQ=DAILY;INTERVAL=3", base: at(time.January, 5), want: at(time.January, 8)},
+		{name: "every other week", rule: "FREQ=WEEKLY;INTERVAL=2", base: at(time.January, 5), want: at(time.January, 19)},
+		{name: "next weekday in week", rule: "FREQ=WEEKLY;BYDAY=MO,WE,FR", base: at(time.January, 5), want: at(time.January, 7)},
+		{name: "weekday in next week", rule: "FREQ=WEEKLY;BYDAY=MO,FR", base: at(time.January, 9), want: at(time.January, 12)},
+		{name: "weekday every other week", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", base: at(time.January, 5), want: at(time.January, 19)},
+		{name: "monthly falls back to last day", rule: "FREQ=MONTHLY", base: at(time.January, 31), want: at(time.February, 28)},
+		{name: "last day of month", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", base: at(time.February, 10), want: at(time.February, 28)},
+		{name: "day later in month", rule: "FREQ=MONTHLY;BYMONTHDAY=15", base: at(time.January, 10), want: at(time.January, 15)},
+		{name: "day in next month", rule: "FREQ=MONTHLY;BYMONTHDAY=15", base: at(time.January, 20), want: at(time.February, 15)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(test.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error: %v", test.rule, err)
+			}
+			if got := recurrence.Next(test.base); !got.Equal(test.want) {
+				t.Fatalf("Next(%v) = %v, want %v", test.base, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRecurrenceEnded(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		next        time.Time
+		occurrences int32
+		want        bool
+	}{
+		{name: "no end", rule: "FREQ=DAILY", next: time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC), occurrences: 1000, want: false},
+		{name: "before until", rule: "FREQ=DAILY;UNTIL=20260131", next: time.Date(2026, time.January, 31, 23, 0, 0, 0, time.UTC), occurrences: 1, want: false},
+		{name: "after until", rule: "FREQ=DAILY;UNTIL=20260131", next: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), occurrences: 1, want: true},
+		{name: "count left", rule: "FREQ=DAILY;COUNT=3", next: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), occurrences: 2, want: false},
+		{name: "count reached", rule: "FREQ=DAILY;COUNT=3", next: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), occurrences: 3, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(test.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrence(%q) error: %v", test.rule, err)
+			}
+			if got := recurrence.Ended(test.next, test.occurrences); got != test.want {
+				t.Fatalf("Ended(%v, %d) = %v, want %v", test.next, test.occurrences, got, test.want)
+			}
+		})
+	}
+}
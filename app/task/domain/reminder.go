@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Reminder fires at RemindAt, or OffsetSeconds before due date of task when RemindAt is empty
+type Reminder struct {
+	ID            int32      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId        int32      `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task          Task       `json:"task" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	RemindAt      *time.Time `json:"remind_at" gorm:"column:remind_at"`
+	OffsetSeconds int64      `json:"offset_seconds" gorm:"column:offset_seconds;not null;default:0"`
+	SnoozedUntil  *time.Time `json:"snoozed_until" gorm:"column:snoozed_until"`
+	FiredAt       *time.Time `json:"fired_at" gorm:"column:fired_at;index"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type RecurrenceAnchor int32
+
+const (
+	// Next occurrence is due one rule step after due date of current one
+	RecurrenceFromDueDate RecurrenceAnchor = iota
+	// Next occurrence is due one rule step after current one is done
+	RecurrenceFromCompletion
+)
+
+// Series links recurring tasks, each occurrence is a task with series id
+type Series struct {
+	ID          int32            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Rule        string           `json:"rule" gorm:"column:rule;not null"`
+	Anchor      RecurrenceAnchor `json:"anchor" gorm:"column:anchor;not null;default:0"`
+	Occurrences int32            `json:"occurrences" gorm:"column:occurrences;not null;default:1"`
+	StoppedAt   *time.Time       `json:"stopped_at" gorm:"column:stopped_at"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"column:created_at"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type Permission int32
+
+const (
+	PermissionNone Permission = iota
+	// Viewer can read task, its comments and attachments
+	PermissionViewer
+	// Editor can also change task
+	PermissionEditor
+	// Owner can also delete and share task, creator of task is always owner
+	PermissionOwner
+)
+
+// Task shared with user, permission on task is also given on its subtasks
+type Share struct {
+	ID         int32      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId     int32      `json:"task_id" gorm:"column:task_id;not null;uniqueIndex:idx_shares_task_user"`
+	Task       Task       `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	UserId     int32      `json:"user_id" gorm:"column:user_id;not null;uniqueIndex:idx_shares_task_user;index"`
+	Permission Permission `json:"permission" gorm:"column:permission;not null"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at"`
+}
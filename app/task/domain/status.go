@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type Status int32
+
+const (
+	// Status is not given, it is derived from IsDone
+	StatusUnspecified Status = iota
+	StatusTodo
+	StatusInProgress
+	StatusBlocked
+	StatusDone
+	StatusCancelled
+)
+
+var statusNames = map[Status]string{
+	StatusUnspecified: "UNSPECIFIED",
+	StatusTodo:        "TODO",
+	StatusInProgress:  "IN_PROGRESS",
+	StatusBlocked:     "BLOCKED",
+	StatusDone:        "DONE",
+	StatusCancelled:   "CANCELLED",
+}
+
+// Task in closed status needs no more work
+var ClosedStatuses = []Status{StatusDone, StatusCancelled}
+
+// Statuses each status can change to, keeping the same status is always allowed
+var statusTransitions = map[Status][]Status{
+	StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusBlocked, StatusDone, StatusCancelled},
+	StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+	StatusDone:       {StatusTodo, StatusInProgress},
+	StatusCancelled:  {StatusTodo},
+}
+
+func (s Status) CanChangeTo(next Status) bool {
+	if s == next {
+		return true
+	}
+	for _, status := range statusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Status) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+func ParseStatus(name string) (Status, error) {
+	for status, status_name := range statusNames {
+		if status_name == name && status != StatusUnspecified {
+			return status, nil
+		}
+	}
+	return StatusUnspecified, fmt.Errorf("%w: status %q is not valid", ErrRecordInvalid, name)
+}
+
+func (s Status) IsDone() bool {
+	return s == StatusDone
+}
+
+func (s Status) IsClosed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+// Find status from IsDone of client which does not know status, current status is kept when it agrees
+func (s Status) FromIsDone(is_done bool) Status {
+	if is_done && !s.IsDone() {
+		return StatusDone
+	}
+	if !is_done && s.IsDone() {
+		return StatusTodo
+	}
+	if s == StatusUnspecified {
+		return StatusTodo
+	}
+	return s
+}
+
+// Record of one status change of task
+type StatusChange struct {
+	ID         int32     `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId     int32     `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task       Task      `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	FromStatus Status    `json:"from_status" gorm:"column:from_status;not null"`
+	ToStatus   Status    `json:"to_status" gorm:"column:to_status;not null"`
+	ChangedBy  int32     `json:"changed_by" gorm:"column:changed_by;not null"`
+	ChangedAt  time.Time `json:"changed_at" gorm:"column:changed_at;not null"`
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStatusCanChangeTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{from: StatusTodo, to: StatusTodo, want: true},
+		{from: StatusTodo, to: StatusInProgress, want: true},
+		{from: StatusTodo, to: StatusDone, want: true},
+		{from: StatusInProgress, to: StatusBlocked, want: true},
+		{from: StatusBlocked, to: StatusInProgress, want: true},
+		{from: StatusBlocked, to: StatusDone, want: false},
+		{from: StatusDone, to: StatusTodo, want: true},
+		{from: StatusDone, to: StatusCancelled, want: false},
+		{from: StatusDone, to: StatusBlocked, want: false},
+		{from: StatusCancelled, to: StatusTodo, want: true},
+		{from: StatusCancelled, to: StatusInProgress, want: false},
+		{from: StatusCancelled, to: StatusCancelled, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from.String()+" to "+test.to.String(), func(t *testing.T) {
+			if got := test.from.CanChangeTo(test.to); got != test.want {
+				t.Fatalf("%v.CanChangeTo(%v) = %v, want %v", test.from, test.to, got, test.want)
+			}
+		})
+	}
+}
+
+func TestStatusFromIsDone(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  Status
+		is_done bool
+		want    Status
+	}{
+		{name: "unspecified not done", status: StatusUnspecified, is_done: false, want: StatusTodo},
+		{name: "unspecified done", status: StatusUnspecified, is_done: true, want: StatusDone},
+		{name: "in progress kept", status: StatusInProgress, is_done: false, want: StatusInProgress},
+		{name: "in progress done", status: StatusInProgress, is_done: true, want: StatusDone},
+		{name: "done kept", status: StatusDone, is_done: true, want: StatusDone},
+		{name: "done reopened", status: StatusDone, is_done: false, want: StatusTodo},
+		{name: "cancelled kept", status: StatusCancelled, is_done: false, want: StatusCancelled},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.status.FromIsDone(test.is_done); got != test.want {
+				t.Fatalf("%v.FromIsDone(%v) = %v, want %v", test.status, test.is_done, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Status
+		invalid bool
+	}{
+		{name: "TODO", want: StatusTodo},
+		{name: "IN_PROGRESS", want: StatusInProgress},
+		{name: "CANCELLED", want: StatusCancelled},
+		{name: "UNSPECIFIED", invalid: true},
+		{name: "done", invalid: true},
+		{name: "", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, err := ParseStatus(test.name)
+			if test.invalid {
+				if !errors.Is(err, ErrRecordInvalid) {
+					t.Fatalf("ParseStatus(%q) error = %v, want %v", test.name, err, ErrRecordInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatus(%q) error: %v", test.name, err)
+			}
+			if status != test.want {
+				t.Fatalf("ParseStatus(%q) = %v, want %v", test.name, status, test.want)
+			}
+		})
+	}
+}
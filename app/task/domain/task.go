@@ -1,16 +1,103 @@
 package domain
 
 import (
+	"fmt"
 	"time"
+
+	tagDomain "todo-go-grpc/app/tag/domain"
+	userDomain "todo-go-grpc/app/user/domain"
+
+	"gorm.io/gorm"
+)
+
+type Priority int32
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "NONE",
+	PriorityLow:    "LOW",
+	PriorityMedium: "MEDIUM",
+	PriorityHigh:   "HIGH",
+	PriorityUrgent: "URGENT",
+}
+
+func (p Priority) Name() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+func ParsePriority(name string) (Priority, error) {
+	for priority, priority_name := range priorityNames {
+		if priority_name == name {
+			return priority, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("%w: priority %q is not valid", ErrRecordInvalid, name)
+}
+
+type CompleteParentRule int32
+
+const (
+	// Parent task can not be done while it still has open children
+	CompleteParentBlock CompleteParentRule = iota
+	// Parent task done makes all of its open children done, unless one of them has an open blocker
+	CompleteParentCascade
 )
 
 type Task struct {
-	ID          int32     `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	IsDone      bool      `json:"is_done"`
-	DoneAt      time.Time `json:"done_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	CreatorId   int32     `json:"creator_id"`
-	TagsId      []int32   `json:"tags_id"`
+	ID            int32           `json:"id"`
+	Name          string          `json:"name"`
+	Description   string          `json:"description"`
+	IsDone        bool            `json:"is_done"`
+	DoneAt        time.Time       `json:"done_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	CreatorId     int32           `json:"creator_id" gorm:"uniqueIndex:idx_tasks_creator_external_id"`
+	UserCreator   userDomain.User `json:"creator" gorm:"foreignKey:CreatorId"`
+	Tags          []tagDomain.Tag `json:"tags" gorm:"many2many:task_tags"`
+	TagsId        []int32         `json:"tags_id" gorm:"-"`
+	Version       int32           `json:"version" gorm:"column:version;not null;default:1"`
+	DueAt         *time.Time      `json:"due_at" gorm:"column:due_at;index"`
+	DueAllDay     bool            `json:"due_all_day" gorm:"column:due_all_day;not null;default:false"`
+	DueTimezone   string          `json:"due_timezone" gorm:"column:due_timezone"`
+	Priority      Priority        `json:"priority" gorm:"column:priority;not null;default:0;index"`
+	ParentId      *int32          `json:"parent_id" gorm:"column:parent_id;index"`
+	Children      []Task          `json:"children" gorm:"foreignKey:ParentId;constraint:OnDelete:SET NULL"`
+	ChildrenDone  int32           `json:"children_done" gorm:"->;-:migration"`
+	ChildrenTotal int32           `json:"children_total" gorm:"->;-:migration"`
+	SeriesId      *int32          `json:"series_id" gorm:"column:series_id;index"`
+	Series        *Series         `json:"series" gorm:"foreignKey:SeriesId"`
+	CommentCount  int32           `json:"comment_count" gorm:"->;-:migration"`
+	AssigneeId    *int32          `json:"assignee_id" gorm:"column:assignee_id;index"`
+	ProjectId     *int32          `json:"project_id" gorm:"column:project_id;index"`
+	Project       *Project        `json:"-" gorm:"foreignKey:ProjectId;constraint:OnDelete:SET NULL"`
+	// IsDone is derived from status
+	Status          Status     `json:"status" gorm:"column:status;not null;default:1;index"`
+	StatusChangedAt *time.Time `json:"status_changed_at" gorm:"column:status_changed_at"`
+	StatusChangedBy *int32     `json:"status_changed_by" gorm:"column:status_changed_by"`
+	// Manual position of task in its list, see RankBetween
+	Rank string `json:"rank" gorm:"column:rank;not null;default:'';index"`
+	// Total time tracked on task in seconds, running timers count until now
+	TrackedSeconds int64 `json:"tracked_seconds" gorm:"->;-:migration"`
+	// Task in trash is hidden from every read but trash itself
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at;index"`
+	// Id of task given by the system it is imported from, or a random UUID given when task is created.
+	// Unique among tasks of creator
+	ExternalId *string `json:"external_id" gorm:"column:external_id;default:gen_random_uuid()::text;uniqueIndex:idx_tasks_creator_external_id"`
+	// Relevance and highlighted name and description of task found by full-text search
+	SearchRank           float32 `json:"search_rank" gorm:"->;-:migration"`
+	NameHighlight        string  `json:"name_highlight" gorm:"->;-:migration"`
+	DescriptionHighlight string  `json:"description_highlight" gorm:"->;-:migration"`
+	// Permission of user who reads task
+	Permission Permission `json:"permission" gorm:"->;-:migration"`
+	// Task is neither created by nor assigned to user who reads it
+	Shared bool `json:"shared" gorm:"-"`
 }
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tagDomain "todo-go-grpc/app/tag/domain"
+)
+
+// Named copy of a task and its subtasks which new tasks are created from
+type Template struct {
+	ID        int32          `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string         `json:"name" gorm:"column:name;not null;uniqueIndex:idx_templates_creator_name"`
+	CreatorId int32          `json:"creator_id" gorm:"column:creator_id;not null;uniqueIndex:idx_templates_creator_name"`
+	Items     []TemplateItem `json:"items" gorm:"foreignKey:TemplateId;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+}
+
+// Task of template, item without parent is the task template is saved from
+type TemplateItem struct {
+	ID          int32         `json:"id" gorm:"primaryKey;autoIncrement"`
+	TemplateId  int32         `json:"template_id" gorm:"column:template_id;not null;index"`
+	ParentId    *int32        `json:"parent_id" gorm:"column:parent_id;index"`
+	Parent      *TemplateItem `json:"-" gorm:"foreignKey:ParentId;constraint:OnDelete:CASCADE"`
+	Name        string        `json:"name" gorm:"column:name;not null"`
+	Description string        `json:"description" gorm:"column:description"`
+	Priority    Priority      `json:"priority" gorm:"column:priority;not null;default:0"`
+	// Due date of task is instantiation time plus offset, no due date when empty
+	DueOffsetSeconds *int64          `json:"due_offset_seconds" gorm:"column:due_offset_seconds"`
+	DueAllDay        bool            `json:"due_all_day" gorm:"column:due_all_day;not null;default:false"`
+	Tags             []tagDomain.Tag `json:"tags" gorm:"many2many:template_item_tags"`
+}
+
+// Replace placeholders of template text with values at given time
+func ExpandPlaceholders(text string, now time.Time) string {
+	year, week := now.ISOWeek()
+	return strings.NewReplacer(
+		"{{date}}", now.Format("2006-01-02"),
+		"{{week}}", strconv.Itoa(week),
+		"{{year}}", strconv.Itoa(year),
+	).Replace(text)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// Time user spent on task, timer of entry is running while EndedAt is empty
+type TimeEntry struct {
+	ID        int32      `json:"id" gorm:"primaryKey;autoIncrement"`
+	TaskId    int32      `json:"task_id" gorm:"column:task_id;not null;index"`
+	Task      Task       `json:"-" gorm:"foreignKey:TaskId;constraint:OnDelete:CASCADE"`
+	UserId    int32      `json:"user_id" gorm:"column:user_id;not null;index;uniqueIndex:idx_time_entries_running,where:ended_at IS NULL"`
+	StartedAt time.Time  `json:"started_at" gorm:"column:started_at;not null;index"`
+	EndedAt   *time.Time `json:"ended_at" gorm:"column:ended_at"`
+	Note      string     `json:"note" gorm:"column:note"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+func (e *TimeEntry) Duration(now time.Time) time.Duration {
+	if e.EndedAt == nil {
+		return now.Sub(e.StartedAt)
+	}
+	return e.EndedAt.Sub(e.StartedAt)
+}
+
+// Tracked time of user in a date range, grouped in different ways
+type TimeReport struct {
+	TotalSeconds int64
+	ByDay        []TimeByDay
+	ByTask       []TimeByTask
+	ByTag        []TimeByTag
+}
+
+type TimeByDay struct {
+	Day     time.Time
+	Seconds int64
+}
+
+type TimeByTask struct {
+	TaskId  int32
+	Name    string
+	Seconds int64
+}
+
+type TimeByTag struct {
+	TagId   int32
+	Value   string
+	Seconds int64
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+type UndoKind string
+
+const (
+	UndoUpdate UndoKind = "UPDATE"
+	UndoDelete UndoKind = "DELETE"
+)
+
+// Fields of task which an update can change
+type TaskState struct {
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	ParentId        *int32     `json:"parent_id"`
+	Rank            string     `json:"rank"`
+	DueAt           *time.Time `json:"due_at"`
+	DueAllDay       bool       `json:"due_all_day"`
+	DueTimezone     string     `json:"due_timezone"`
+	Priority        Priority   `json:"priority"`
+	AssigneeId      *int32     `json:"assignee_id"`
+	ProjectId       *int32     `json:"project_id"`
+	Status          Status     `json:"status"`
+	IsDone          bool       `json:"is_done"`
+	DoneAt          time.Time  `json:"done_at"`
+	StatusChangedAt *time.Time `json:"status_changed_at"`
+	StatusChangedBy *int32     `json:"status_changed_by"`
+	// Empty when tags of task were not loaded
+	TagsId []int32 `json:"tags_id"`
+}
+
+func NewTaskState(task *Task) *TaskState {
+	state := &TaskState{
+		Name:            task.Name,
+		Description:     task.Description,
+		ParentId:        task.ParentId,
+		Rank:            task.Rank,
+		DueAt:           task.DueAt,
+		DueAllDay:       task.DueAllDay,
+		DueTimezone:     task.DueTimezone,
+		Priority:        task.Priority,
+		AssigneeId:      task.AssigneeId,
+		ProjectId:       task.ProjectId,
+		Status:          task.Status,
+		IsDone:          task.IsDone,
+		DoneAt:          task.DoneAt,
+		StatusChangedAt: task.StatusChangedAt,
+		StatusChangedBy: task.StatusChangedBy,
+	}
+	if task.Tags != nil {
+		state.TagsId = []int32{}
+		for _, tag := range task.Tags {
+			state.TagsId = append(state.TagsId, tag.ID)
+		}
+	}
+	return state
+}
+
+// Get columns of task which bring it back to state, tags are not included.
+// Ownership is never given back by undo, so creator is not part of state
+func (s *TaskState) Map() map[string]any {
+	return map[string]any{
+		"name":              s.Name,
+		"description":       s.Description,
+		"parent_id":         s.ParentId,
+		"rank":              s.Rank,
+		"due_at":            s.DueAt,
+		"due_all_day":       s.DueAllDay,
+		"due_timezone":      s.DueTimezone,
+		"priority":          s.Priority,
+		"assignee_id":       s.AssigneeId,
+		"project_id":        s.ProjectId,
+		"status":            s.Status,
+		"is_done":           s.IsDone,
+		"done_at":           s.DoneAt,
+		"status_changed_at": s.StatusChangedAt,
+		"status_changed_by": s.StatusChangedBy,
+	}
+}
+
+// Task changed by an operation
+type UndoTask struct {
+	TaskId int32 `json:"task_id"`
+	// Version of task right after operation, undo fails when task is changed since
+	Version int32 `json:"version"`
+	// State of task before update, empty for deleted task
+	Previous *TaskState `json:"previous"`
+	// Task is created by operation, for example next occurrence of series, so undo deletes it
+	Created bool `json:"created"`
+}
+
+type UndoTasks []UndoTask
+
+func (t UndoTasks) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+func (t *UndoTasks) Scan(value any) error {
+	return scanJSON(value, t)
+}
+
+// Inverse of the last undoable operation of user, a new operation replaces it
+type UndoOperation struct {
+	ID     int32     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserId int32     `json:"user_id" gorm:"column:user_id;not null;uniqueIndex"`
+	Kind   UndoKind  `json:"kind" gorm:"column:kind;not null"`
+	Tasks  UndoTasks `json:"tasks" gorm:"column:tasks;type:jsonb;not null"`
+	// Time deleted tasks were moved to trash
+	TrashedAt *time.Time `json:"trashed_at" gorm:"column:trashed_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"column:created_at;not null"`
+}
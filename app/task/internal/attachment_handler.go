@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	api "todo-go-grpc/app/task/api"
+	"todo-go-grpc/app/task/blobstore"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const attachmentChunkSize = 64 * 1024
+
+func transferDomainToAttachment(in *domain.Attachment) *api.Attachment {
+	return &api.Attachment{
+		Id:          in.ID,
+		TaskId:      in.TaskId,
+		UploaderId:  in.UploaderId,
+		Name:        in.Name,
+		Size:        in.Size,
+		ContentType: in.ContentType,
+		Sha256:      in.Sha256,
+		CreatedTime: timestamppb.New(in.CreatedAt),
+	}
+}
+
+func newStorageKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Blobs are deleted after their rows, a failed delete only leaves an unused blob behind
+func (serverInstance *server) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := serverInstance.blobStore.Delete(ctx, key); err != nil {
+			log.Printf("Delete blob %v error: %v", key, err)
+		}
+	}
+}
+
+func (serverInstance *server) UploadAttachment(stream api.TaskHandler_UploadAttachmentServer) error {
+	ctx := stream.Context()
+	user_id := getUserId(ctx)
+
+	first, err := stream.Recv()
+	if err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+	info := first.GetInfo()
+	if err := info.Valid(); err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, info.TaskId, domain.PermissionEditor); err != nil {
+		return err
+	}
+
+	used_size, err := serverInstance.attachmentRepo.TotalSizeByUser(ctx, user_id)
+	if err != nil {
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	// Spool bytes to a temporary file to know size and hash before storing them
+	file, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	hash := sha256.New()
+	var size int64
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		chunk := req.GetChunk()
+		size += int64(len(chunk))
+		if size > serverInstance.config.MaxAttachmentSize {
+			return grpc_status.Error(codes.ResourceExhausted, domain.ErrAttachmentTooLarge.Error())
+		}
+		if used_size+size > serverInstance.config.MaxUserAttachmentSize {
+			return grpc_status.Error(codes.ResourceExhausted, domain.ErrAttachmentQuota.Error())
+		}
+
+		hash.Write(chunk)
+		if _, err := file.Write(chunk); err != nil {
+			return grpc_status.Error(codes.Unknown, err.Error())
+		}
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	if err := serverInstance.blobStore.Put(ctx, key, file, size); err != nil {
+		log.Println(err.Error())
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	attachment, err := serverInstance.attachmentRepo.Create(ctx, &domain.Attachment{
+		TaskId:      info.TaskId,
+		UploaderId:  user_id,
+		Name:        info.Name,
+		Size:        size,
+		ContentType: info.ContentType,
+		Sha256:      hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+	}, serverInstance.config.MaxUserAttachmentSize)
+
+	if err != nil {
+		log.Println(err.Error())
+		serverInstance.deleteBlobs(ctx, []string{key})
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrAttachmentQuota) {
+			return grpc_status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return stream.SendAndClose(transferDomainToAttachment(attachment))
+}
+
+func (serverInstance *server) DownloadAttachment(req *api.DownloadAttachmentReq, stream api.TaskHandler_DownloadAttachmentServer) error {
+	if err := req.Valid(); err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx := stream.Context()
+
+	attachment, err := serverInstance.attachmentRepo.GetByID(ctx, req.Id)
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrAttachmentNotExists) {
+			return grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	if err := serverInstance.checkPermission(ctx, attachment.TaskId, domain.PermissionViewer); err != nil {
+		return err
+	}
+
+	reader, err := serverInstance.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, blobstore.ErrBlobNotExists) {
+			return grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	defer reader.Close()
+
+	if err := stream.Send(&api.DownloadAttachmentRes{
+		Data: &api.DownloadAttachmentRes_Info{Info: transferDomainToAttachment(attachment)},
+	}); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, attachmentChunkSize)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&api.DownloadAttachmentRes{
+				Data: &api.DownloadAttachmentRes_Chunk{Chunk: buffer[:n]},
+			}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return grpc_status.Error(codes.Unknown, err.Error())
+		}
+	}
+}
+
+func (serverInstance *server) ListAttachments(ctx context.Context, req *api.ListAttachmentsReq) (*api.ListAttachment, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, req.TaskId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	attachments, err := serverInstance.attachmentRepo.FetchByTask(ctx, req.TaskId)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	attachments_rs := &api.ListAttachment{Attachments: []*api.Attachment{}}
+	for _, attachment := range attachments {
+		attachments_rs.Attachments = append(attachments_rs.Attachments, transferDomainToAttachment(&attachment))
+	}
+
+	return attachments_rs, nil
+}
+
+func (serverInstance *server) DeleteAttachment(ctx context.Context, req *api.DeleteAttachmentReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	attachment, err := serverInstance.attachmentRepo.GetByID(ctx, req.Id)
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrAttachmentNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+	if err := serverInstance.checkPermission(ctx, attachment.TaskId, domain.PermissionEditor); err != nil {
+		return nil, err
+	}
+
+	attachment, err = serverInstance.attachmentRepo.Delete(ctx, req.Id)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrAttachmentNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	serverInstance.deleteBlobs(ctx, []string{attachment.StorageKey})
+	return &emptypb.Empty{}, nil
+}
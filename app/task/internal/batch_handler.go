@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+)
+
+func (serverInstance *server) checkBatchSize(items int) error {
+	if items > serverInstance.config.MaxBatchItems {
+		return grpc_status.Error(codes.InvalidArgument, fmt.Sprintf("Batch must not have more than %d items", serverInstance.config.MaxBatchItems))
+	}
+	return nil
+}
+
+// Item which failed in repository, items aborted along with another one are Aborted
+func transferBatchResults(results []*api.BatchItemResult, indexes []int, domain_results []domain.BatchResult, code func(err error) codes.Code) {
+	for i, result := range domain_results {
+		if result.Err == nil {
+			results[indexes[i]] = &api.BatchItemResult{Task: transferDomainToBasicTask(result.Task)}
+			continue
+		}
+
+		item_code := codes.Aborted
+		if !errors.Is(result.Err, domain.ErrBatchAborted) {
+			log.Println(result.Err.Error())
+			item_code = code(result.Err)
+		}
+		results[indexes[i]] = transferErrorToBatchResult(grpc_status.Error(item_code, result.Err.Error()))
+	}
+}
+
+func transferErrorToBatchResult(err error) *api.BatchItemResult {
+	status := grpc_status.Convert(err)
+	return &api.BatchItemResult{Error: &api.ItemError{Code: int32(status.Code()), Message: status.Message()}}
+}
+
+// Items which failed before repository is called abort the whole atomic batch
+func abortBatchResults(results []*api.BatchItemResult) {
+	for i := range results {
+		if results[i] == nil {
+			results[i] = transferErrorToBatchResult(grpc_status.Error(codes.Aborted, domain.ErrBatchAborted.Error()))
+		}
+	}
+}
+
+func batchResponse(results []*api.BatchItemResult) *api.BatchRes {
+	response := &api.BatchRes{Results: results}
+	for _, result := range results {
+		if result.Error == nil {
+			response.Succeeded++
+		}
+	}
+	return response
+}
+
+// Every user is checked once per batch, items assigned to the same user share the result
+func (serverInstance *server) checkBatchUser(ctx context.Context, checked map[int32]error, user_id int32) error {
+	if err, ok := checked[user_id]; ok {
+		return err
+	}
+	err := serverInstance.checkUser(ctx, user_id)
+	checked[user_id] = err
+	return err
+}
+
+func (serverInstance *server) BatchCreate(ctx context.Context, req *api.BatchCreateReq) (*api.BatchRes, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := serverInstance.checkBatchSize(len(req.Items)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*api.BatchItemResult, len(req.Items))
+	users := map[int32]error{}
+	infos := []*domain.Task{}
+	indexes := []int{}
+	for i, item := range req.Items {
+		if err := item.Valid(); err != nil {
+			results[i] = transferErrorToBatchResult(grpc_status.Error(codes.InvalidArgument, err.Error()))
+			continue
+		}
+		if err := serverInstance.checkBatchUser(ctx, users, item.AssigneeId); err != nil {
+			results[i] = transferErrorToBatchResult(err)
+			continue
+		}
+		infos = append(infos, transferCreateReqToDomain(item))
+		indexes = append(indexes, i)
+	}
+	if req.Atomic && len(infos) != len(req.Items) || len(infos) == 0 {
+		abortBatchResults(results)
+		return batchResponse(results), nil
+	}
+
+	domain_results, err := serverInstance.repo.BatchCreate(ctx, getUserId(ctx), infos, req.Atomic)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	transferBatchResults(results, indexes, domain_results, createErrorCode)
+	return batchResponse(results), nil
+}
+
+func (serverInstance *server) BatchUpdate(ctx context.Context, req *api.BatchUpdateReq) (*api.BatchRes, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := serverInstance.checkBatchSize(len(req.Items)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*api.BatchItemResult, len(req.Items))
+	users := map[int32]error{}
+	updates := []domain.TaskUpdate{}
+	indexes := []int{}
+	for i, item := range req.Items {
+		if err := item.Valid(); err != nil {
+			results[i] = transferErrorToBatchResult(grpc_status.Error(codes.InvalidArgument, err.Error()))
+			continue
+		}
+		if err := serverInstance.checkBatchUser(ctx, users, item.NewTaskInfo.AssigneeId); err != nil {
+			results[i] = transferErrorToBatchResult(err)
+			continue
+		}
+
+		data := transferBasicTaskToDomain(item.NewTaskInfo)
+		data.Version = item.Version
+		updates = append(updates, domain.TaskUpdate{
+			Id:         item.Id,
+			Info:       data,
+			TagsAdd:    item.TagsAdded,
+			TagsRemove: item.TagsDeleted,
+			Force:      item.Force,
+		})
+		indexes = append(indexes, i)
+	}
+	if req.Atomic && len(updates) != len(req.Items) || len(updates) == 0 {
+		abortBatchResults(results)
+		return batchResponse(results), nil
+	}
+
+	domain_results, err := serverInstance.repo.BatchUpdate(ctx, getUserId(ctx), updates, req.Atomic)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	transferBatchResults(results, indexes, domain_results, updateErrorCode)
+	return batchResponse(results), nil
+}
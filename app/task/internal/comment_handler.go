@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToComment(in *domain.Comment) *api.Comment {
+	comment := &api.Comment{
+		Id:          in.ID,
+		TaskId:      in.TaskId,
+		AuthorId:    in.AuthorId,
+		Body:        in.Body,
+		CreatedTime: timestamppb.New(in.CreatedAt),
+	}
+	if in.EditedAt != nil {
+		comment.EditedTime = timestamppb.New(*in.EditedAt)
+	}
+	return comment
+}
+
+func (serverInstance *server) AddComment(ctx context.Context, req *api.AddCommentReq) (*api.Comment, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, req.TaskId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	comment, err := serverInstance.commentRepo.Create(ctx, &domain.Comment{
+		TaskId:   req.TaskId,
+		AuthorId: getUserId(ctx),
+		Body:     req.Body,
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToComment(comment), nil
+}
+
+func (serverInstance *server) ListComments(ctx context.Context, req *api.ListCommentsReq) (*api.ListComment, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, req.TaskId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	comments, err := serverInstance.commentRepo.FetchByTask(ctx, req.TaskId, req.PageToken, req.PageSize)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	comments_rs := &api.ListComment{Comments: []*api.Comment{}}
+	for _, comment := range comments {
+		comments_rs.Comments = append(comments_rs.Comments, transferDomainToComment(&comment))
+	}
+
+	return comments_rs, nil
+}
+
+func (serverInstance *server) EditComment(ctx context.Context, req *api.EditCommentReq) (*api.Comment, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	comment, err := serverInstance.commentRepo.Update(ctx, req.Id, getUserId(ctx), req.Body)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrCommentNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToComment(comment), nil
+}
+
+func (serverInstance *server) DeleteComment(ctx context.Context, req *api.DeleteCommentReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.commentRepo.Delete(ctx, req.Id, getUserId(ctx)); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrCommentNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToDependency(in *domain.Dependency) *api.Dependency {
+	return &api.Dependency{
+		TaskId:      in.TaskId,
+		BlockerId:   in.BlockerId,
+		CreatedTime: timestamppb.New(in.CreatedAt),
+	}
+}
+
+func (serverInstance *server) AddDependency(ctx context.Context, req *api.AddDependencyReq) (*api.Dependency, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	dependency, err := serverInstance.dependencyRepo.Create(ctx, getUserId(ctx), &domain.Dependency{
+		TaskId:    req.TaskId,
+		BlockerId: req.BlockerId,
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrDependencyCycle) {
+			return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, domain.ErrDependencyExists) {
+			return nil, grpc_status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToDependency(dependency), nil
+}
+
+func (serverInstance *server) RemoveDependency(ctx context.Context, req *api.RemoveDependencyReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.dependencyRepo.Delete(ctx, req.TaskId, req.BlockerId, getUserId(ctx)); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) || errors.Is(err, domain.ErrDependencyNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (serverInstance *server) ListDependencies(ctx context.Context, req *api.ListDependenciesReq) (*api.ListDependency, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	blockers, blocking, err := serverInstance.dependencyRepo.FetchByTask(ctx, req.TaskId, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	dependencies_rs := &api.ListDependency{Blockers: []*api.BasicTask{}, Blocking: []*api.BasicTask{}}
+	for _, task := range blockers {
+		dependencies_rs.Blockers = append(dependencies_rs.Blockers, transferDomainToBasicTask(&task))
+	}
+	for _, task := range blocking {
+		dependencies_rs.Blocking = append(dependencies_rs.Blocking, transferDomainToBasicTask(&task))
+	}
+
+	return dependencies_rs, nil
+}
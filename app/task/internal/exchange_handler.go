@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"log"
+	"sort"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/taskfile"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+)
+
+// Bytes written to it are sent as chunks of export
+type exportStreamWriter struct {
+	stream api.TaskHandler_ExportServer
+}
+
+func (w *exportStreamWriter) Write(data []byte) (int, error) {
+	chunk := make([]byte, len(data))
+	copy(chunk, data)
+	if err := w.stream.Send(&api.ExportChunk{Data: chunk}); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// Bytes of chunks of import are read from it
+type importStreamReader struct {
+	stream api.TaskHandler_ImportServer
+	buffer []byte
+}
+
+func (r *importStreamReader) Read(data []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buffer = req.GetChunk()
+	}
+
+	n := copy(data, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+func importErrorCode(err error) codes.Code {
+	if errors.Is(err, domain.ErrRecordInvalid) || errors.Is(err, domain.ErrTaskCycle) {
+		return codes.InvalidArgument
+	}
+	if errors.Is(err, domain.ErrTaskExists) {
+		return codes.AlreadyExists
+	}
+	return createErrorCode(err)
+}
+
+var importStatuses = map[domain.ImportStatus]api.ImportStatus{
+	domain.ImportCreated: api.ImportStatus_IMPORT_CREATED,
+	domain.ImportSkipped: api.ImportStatus_IMPORT_SKIPPED,
+	domain.ImportFailed:  api.ImportStatus_IMPORT_FAILED,
+}
+
+func transferImportResultToProto(in *domain.ImportResult) *api.ImportRecordResult {
+	result := &api.ImportRecordResult{
+		Row:        int32(in.Row),
+		ExternalId: in.ExternalId,
+		Status:     importStatuses[in.Status],
+		TaskId:     in.TaskId,
+	}
+	if in.Err != nil {
+		result.Error = &api.ItemError{Code: int32(importErrorCode(in.Err)), Message: in.Err.Error()}
+	}
+	return result
+}
+
+func (serverInstance *server) Export(req *api.ExportReq, stream api.TaskHandler_ExportServer) error {
+	if err := req.Valid(); err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	writer, err := taskfile.NewWriter(taskfile.Format(req.Format.String()), &exportStreamWriter{stream: stream})
+	if err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.repo.Export(ctx, getUserId(ctx), writer.Write); err != nil {
+		log.Println(err.Error())
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	if err := writer.Close(); err != nil {
+		log.Println(err.Error())
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return nil
+}
+
+// Invalid record fails alone, only a file which can not be read at all fails import
+func (serverInstance *server) Import(stream api.TaskHandler_ImportServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+	options := first.GetOptions()
+	if err := options.Valid(); err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reader, err := taskfile.NewReader(taskfile.Format(options.Format.String()), &importStreamReader{stream: stream})
+	if err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Records which can be read go to repository, the others fail right away
+	records := []domain.TaskRecord{}
+	rows := []int{}
+	results := []*api.ImportRecordResult{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return grpc_status.FromContextError(err).Err()
+		}
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var record_err *taskfile.RecordError
+		if errors.As(err, &record_err) {
+			results = append(results, transferImportResultToProto(&domain.ImportResult{Row: record_err.Row, Status: domain.ImportFailed, Err: record_err.Err}))
+			continue
+		}
+		if err != nil {
+			if _, ok := grpc_status.FromError(err); ok {
+				return err
+			}
+			return grpc_status.Error(codes.InvalidArgument, err.Error())
+		}
+		records = append(records, *record)
+		rows = append(rows, reader.Row())
+	}
+
+	domain_results, err := serverInstance.repo.Import(ctx, getUserId(ctx), records, domain.ImportOptions{
+		DryRun:            options.DryRun,
+		CreateMissingTags: options.CreateMissingTags,
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	for i := range domain_results {
+		domain_results[i].Row = rows[i]
+		results = append(results, transferImportResultToProto(&domain_results[i]))
+	}
+	// Results follow the order of file
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Row < results[j].Row
+	})
+
+	res := &api.ImportRes{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case api.ImportStatus_IMPORT_CREATED:
+			res.Created++
+		case api.ImportStatus_IMPORT_SKIPPED:
+			res.Skipped++
+		case api.ImportStatus_IMPORT_FAILED:
+			res.Failed++
+		}
+	}
+
+	return stream.SendAndClose(res)
+}
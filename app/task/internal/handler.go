@@ -3,52 +3,139 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"todo-go-grpc/app/auth"
+	"todo-go-grpc/app/idempotency"
+	response_handler "todo-go-grpc/app/response_handler"
 	api "todo-go-grpc/app/task/api"
+	"todo-go-grpc/app/task/blobstore"
 	domain "todo-go-grpc/app/task/domain"
 	repository "todo-go-grpc/app/task/repository"
+	"todo-go-grpc/app/task/userservice"
+	"todo-go-grpc/app/task/watch"
 
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	grpc_status "google.golang.org/grpc/status"
+	proto "google.golang.org/protobuf/proto"
+	protoiface "google.golang.org/protobuf/runtime/protoiface"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+type Config struct {
+	// Maximum size of one attachment in bytes
+	MaxAttachmentSize int64
+	// Maximum total size of attachments uploaded by one user in bytes
+	MaxUserAttachmentSize int64
+	// Maximum number of items in one batch create or update
+	MaxBatchItems int
+}
+
 type server struct {
-	repo repository.TaskRepository
+	config         Config
+	repo           repository.TaskRepository
+	reminderRepo   repository.ReminderRepository
+	commentRepo    repository.CommentRepository
+	attachmentRepo repository.AttachmentRepository
+	shareRepo      repository.ShareRepository
+	projectRepo    repository.ProjectRepository
+	dependencyRepo repository.DependencyRepository
+	timeEntryRepo  repository.TimeEntryRepository
+	templateRepo   repository.TemplateRepository
+	historyRepo    repository.HistoryRepository
+	idempotency    *idempotency.Store
+	broker         watch.Broker
+	blobStore      blobstore.BlobStore
+	userService    userservice.UserService
 	api.UnimplementedTaskHandlerServer
 }
 
-func RegisterGrpc(gserver *grpc.Server, repo repository.TaskRepository) {
+func RegisterGrpc(gserver *grpc.Server, config Config, repo repository.TaskRepository, reminderRepo repository.ReminderRepository, commentRepo repository.CommentRepository, attachmentRepo repository.AttachmentRepository, shareRepo repository.ShareRepository, projectRepo repository.ProjectRepository, dependencyRepo repository.DependencyRepository, timeEntryRepo repository.TimeEntryRepository, templateRepo repository.TemplateRepository, historyRepo repository.HistoryRepository, idempotencyStore *idempotency.Store, broker watch.Broker, blobStore blobstore.BlobStore, userService userservice.UserService) {
 	taskServer := &server{
-		repo: repo,
+		config:         config,
+		repo:           repo,
+		reminderRepo:   reminderRepo,
+		commentRepo:    commentRepo,
+		attachmentRepo: attachmentRepo,
+		shareRepo:      shareRepo,
+		projectRepo:    projectRepo,
+		dependencyRepo: dependencyRepo,
+		timeEntryRepo:  timeEntryRepo,
+		templateRepo:   templateRepo,
+		historyRepo:    historyRepo,
+		idempotency:    idempotencyStore,
+		broker:         broker,
+		blobStore:      blobStore,
+		userService:    userService,
 	}
 
 	api.RegisterTaskHandlerServer(gserver, taskServer)
 }
 
 func transferDomainToTask(in *domain.Task) *api.Task {
-	return &api.Task{
-		Id:          in.ID,
-		Name:        in.Name,
-		Description: in.Description,
-		IsDone:      in.IsDone,
-		DonedTime:   timestamppb.New(in.DoneAt),
-		CreatedTime: timestamppb.New(in.CreatedAt),
+	task := &api.Task{
+		Id:           in.ID,
+		Name:         in.Name,
+		Description:  in.Description,
+		IsDone:       in.IsDone,
+		DonedTime:    timestamppb.New(in.DoneAt),
+		CreatedTime:  timestamppb.New(in.CreatedAt),
+		Version:      in.Version,
+		Due:          transferDomainToDue(in),
+		Priority:     api.Priority(in.Priority),
+		ParentId:     transferOptionalIdToProto(in.ParentId),
+		Series:       transferDomainToSeries(in.Series),
+		CommentCount: in.CommentCount,
+		AssigneeId:   transferOptionalIdToProto(in.AssigneeId),
+		Permission:   api.Permission(in.Permission),
+		Shared:       in.Shared,
+		ProjectId:    transferOptionalIdToProto(in.ProjectId),
+		Status:       api.Status(in.Status),
+		TrackedTime:  transferSecondsToProto(in.TrackedSeconds),
+		Children:     []*api.BasicTask{},
+		Progress: &api.Progress{
+			Done:  in.ChildrenDone,
+			Total: in.ChildrenTotal,
+		},
 	}
+	if in.DeletedAt.Valid {
+		task.DeletedTime = timestamppb.New(in.DeletedAt.Time)
+	}
+	if in.NameHighlight != "" {
+		task.SearchRank = in.SearchRank
+		task.NameHighlight = in.NameHighlight
+		task.DescriptionHighlight = in.DescriptionHighlight
+	}
+	if in.StatusChangedAt != nil {
+		task.StatusChangedTime = timestamppb.New(*in.StatusChangedAt)
+		task.StatusChangedBy = transferOptionalIdToProto(in.StatusChangedBy)
+	}
+	for _, child := range in.Children {
+		task.Children = append(task.Children, transferDomainToBasicTask(&child))
+	}
+	return task
 }
 
 func transferTaskToDomain(in *api.Task) *domain.Task {
-	return &domain.Task{
+	task := &domain.Task{
 		ID:          in.Id,
 		Name:        in.Name,
 		Description: in.Description,
 		IsDone:      in.IsDone,
 		DoneAt:      in.DonedTime.AsTime(),
 		CreatedAt:   in.CreatedTime.AsTime(),
+		Version:     in.Version,
+		Priority:    domain.Priority(in.Priority),
+		ParentId:    transferOptionalIdToDomain(in.ParentId),
+		Status:      domain.Status(in.Status),
 	}
+	transferDueToDomain(in.Due, task)
+	return task
 }
 
 func transferDomainToBasicTask(in *domain.Task) *api.BasicTask {
@@ -60,11 +147,19 @@ func transferDomainToBasicTask(in *domain.Task) *api.BasicTask {
 		DonedTime:   timestamppb.New(in.DoneAt),
 		CreatorId:   in.CreatorId,
 		CreatedTime: timestamppb.New(in.CreatedAt),
+		Version:     in.Version,
+		Due:         transferDomainToDue(in),
+		Priority:    api.Priority(in.Priority),
+		ParentId:    transferOptionalIdToProto(in.ParentId),
+		SeriesId:    transferOptionalIdToProto(in.SeriesId),
+		AssigneeId:  transferOptionalIdToProto(in.AssigneeId),
+		ProjectId:   transferOptionalIdToProto(in.ProjectId),
+		Status:      api.Status(in.Status),
 	}
 }
 
 func transferBasicTaskToDomain(in *api.BasicTask) *domain.Task {
-	return &domain.Task{
+	task := &domain.Task{
 		ID:          in.Id,
 		Name:        in.Name,
 		Description: in.Description,
@@ -72,17 +167,191 @@ func transferBasicTaskToDomain(in *api.BasicTask) *domain.Task {
 		DoneAt:      in.DonedTime.AsTime(),
 		CreatorId:   in.CreatorId,
 		CreatedAt:   in.CreatedTime.AsTime(),
+		Version:     in.Version,
+		Priority:    domain.Priority(in.Priority),
+		ParentId:    transferOptionalIdToDomain(in.ParentId),
+		AssigneeId:  transferOptionalIdToDomain(in.AssigneeId),
+		ProjectId:   transferOptionalIdToDomain(in.ProjectId),
+		Status:      domain.Status(in.Status),
+	}
+	transferDueToDomain(in.Due, task)
+	return task
+}
+
+// Zero id in proto means no reference, for example task without parent
+func transferOptionalIdToDomain(id int32) *int32 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func transferOptionalIdToProto(id *int32) int32 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func transferDomainToDue(in *domain.Task) *api.DueDate {
+	if in.DueAt == nil {
+		return nil
+	}
+	return &api.DueDate{
+		Time:     timestamppb.New(*in.DueAt),
+		AllDay:   in.DueAllDay,
+		Timezone: in.DueTimezone,
+	}
+}
+
+// Date only due is moved to the start of its day in timezone
+func transferDueToDomain(in *api.DueDate, out *domain.Task) {
+	if in == nil || in.Time == nil {
+		return
+	}
+
+	location, err := time.LoadLocation(in.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	due_at := in.Time.AsTime().In(location)
+	if in.AllDay {
+		due_at = time.Date(due_at.Year(), due_at.Month(), due_at.Day(), 0, 0, 0, 0, location)
 	}
+
+	out.DueAt = &due_at
+	out.DueAllDay = in.AllDay
+	out.DueTimezone = location.String()
+}
+
+// Find range [from, to) of due filter, day and week follow the timezone and week starts on Monday
+func dueRange(filter api.DueFilter, location *time.Location, now time.Time) (time.Time, time.Time) {
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	switch filter {
+	case api.DueFilter_DUE_TODAY:
+		return today, today.AddDate(0, 0, 1)
+	case api.DueFilter_DUE_THIS_WEEK:
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return monday, monday.AddDate(0, 0, 7)
+	}
+	return time.Time{}, time.Time{}
+}
+
+// User id comes from token checked by auth interceptor, which rejects calls without one as unauthenticated.
+// Zero is no user, so a handler reached without interceptor fails every permission check
+func getUserId(ctx context.Context) int32 {
+	user_id, _ := auth.UserId(ctx)
+	return user_id
+}
+
+// User is checked by user service, zero id means no user, for example task is not assigned
+func (serverInstance *server) checkUser(ctx context.Context, user_id int32) error {
+	if user_id == 0 {
+		return nil
+	}
+
+	exists, err := serverInstance.userService.IsExists(ctx, user_id)
+	if err != nil {
+		log.Println(err.Error())
+		return grpc_status.Error(codes.Unavailable, err.Error())
+	}
+	if !exists {
+		return grpc_status.Error(codes.InvalidArgument, fmt.Errorf("%w: user %d", domain.ErrUserNotExists, user_id).Error())
+	}
+	return nil
+}
+
+// Check caller has at least level of permission on task before touching what belongs to it
+func (serverInstance *server) checkPermission(ctx context.Context, task_id int32, level domain.Permission) error {
+	if err := serverInstance.repo.CheckPermission(ctx, task_id, getUserId(ctx), level); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return grpc_status.Error(codes.Unknown, err.Error())
+	}
+	return nil
+}
+
+// Attach current state of tasks which version is different from expected
+func (serverInstance *server) responseVersionMismatch(ctx context.Context, err error, versions map[int32]int32) error {
+	details := []protoiface.MessageV1{}
+	for id, version := range versions {
+		task, get_err := serverInstance.repo.GetByID(ctx, id, getUserId(ctx))
+		if get_err == nil && task.Version != version {
+			details = append(details, transferDomainToBasicTask(task))
+		}
+	}
+
+	return response_handler.ResponseErrorAborted(err, details...)
 }
 
 func (serverInstance *server) List(ctx context.Context, req *api.ListReq) (*api.ListTask, error) {
-	// TODO: Get creator id
-	var creator_id int32 = 1
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	creator_id := getUserId(ctx)
 
 	conditions_map := map[string]any{}
 	if req.Name != "" {
 		conditions_map["name"] = req.Name
 	}
+	if strings.TrimSpace(req.Query) != "" {
+		conditions_map["query"] = req.Query
+	}
+	if req.DueFrom != nil {
+		conditions_map["due_from"] = req.DueFrom.AsTime()
+	}
+	if req.DueTo != nil {
+		conditions_map["due_to"] = req.DueTo.AsTime()
+	}
+	switch req.DueFilter {
+	case api.DueFilter_OVERDUE:
+		conditions_map["overdue"] = time.Now()
+	case api.DueFilter_DUE_TODAY, api.DueFilter_DUE_THIS_WEEK:
+		location, _ := time.LoadLocation(req.Timezone)
+		from, to := dueRange(req.DueFilter, location, time.Now())
+		conditions_map["due_from"], conditions_map["due_to"] = from, to
+	}
+	if len(req.Priorities) != 0 {
+		priorities := []domain.Priority{}
+		for _, priority := range req.Priorities {
+			priorities = append(priorities, domain.Priority(priority))
+		}
+		conditions_map["priorities"] = priorities
+	}
+	if req.ParentId != 0 {
+		conditions_map["parent_id"] = req.ParentId
+	}
+	if req.AllLevels {
+		conditions_map["all_levels"] = true
+	}
+	if req.Ownership != api.Ownership_OWNERSHIP_UNSPECIFIED {
+		conditions_map["ownership"] = req.Ownership.String()
+	}
+	if req.ProjectId != 0 {
+		conditions_map["project_id"] = req.ProjectId
+	}
+	if len(req.Statuses) != 0 {
+		statuses := []domain.Status{}
+		for _, status := range req.Statuses {
+			statuses = append(statuses, domain.Status(status))
+		}
+		conditions_map["statuses"] = statuses
+	}
+	if req.Actionable {
+		conditions_map["actionable"] = true
+	}
+	if req.IncludeArchived {
+		conditions_map["include_archived"] = true
+	}
 	// if req.TagsId != nil || len(req.TagsId) != 0 {
 	// 	conditions_map["tags"] = req.TagsId
 	// }
@@ -109,13 +378,16 @@ func (serverInstance *server) List(ctx context.Context, req *api.ListReq) (*api.
 }
 
 func (serverInstance *server) Get(ctx context.Context, req *api.GetReq) (*api.Task, error) {
-	task, err := serverInstance.repo.GetByID(ctx, req.Id)
+	task, err := serverInstance.repo.GetByID(ctx, req.Id, getUserId(ctx))
 
 	if err != nil {
 		log.Println(err.Error())
 		if errors.Is(err, domain.ErrTaskNotExists) {
 			return nil, grpc_status.Error(codes.NotFound, err.Error())
 		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, grpc_status.Error(codes.Unknown, err.Error())
 	}
 
@@ -123,57 +395,160 @@ func (serverInstance *server) Get(ctx context.Context, req *api.GetReq) (*api.Ta
 }
 
 func (serverInstance *server) Create(ctx context.Context, req *api.CreateReq) (*api.BasicTask, error) {
-	// TODO: Get creator id
-	var creator_id int32 = 1
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkUser(ctx, req.AssigneeId); err != nil {
+		return nil, err
+	}
+
+	// Retry with the same idempotency key gets the task created by the first request
+	request := proto.Clone(req).(*api.CreateReq)
+	request.IdempotencyKey = ""
+	creator_id := getUserId(ctx)
+	res, err := serverInstance.idempotency.Do(ctx, "task.Create", creator_id, idempotency.Key(ctx, req.IdempotencyKey), request, &api.BasicTask{}, func() (proto.Message, error) {
+		new_task, err := serverInstance.repo.Create(ctx, creator_id, transferCreateReqToDomain(request))
+
+		if err != nil {
+			log.Println(err.Error())
+			return nil, grpc_status.Error(createErrorCode(err), err.Error())
+		}
+
+		return transferDomainToBasicTask(new_task), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
+	return res.(*api.BasicTask), nil
+}
+
+func transferCreateReqToDomain(req *api.CreateReq) *domain.Task {
 	data := &domain.Task{
 		Name:        req.Name,
 		Description: req.Description,
 		IsDone:      req.IsDone,
+		Priority:    domain.Priority(req.Priority),
+		ParentId:    transferOptionalIdToDomain(req.ParentId),
+		Series:      transferRecurrenceToSeries(req.Recurrence),
+		AssigneeId:  transferOptionalIdToDomain(req.AssigneeId),
+		ProjectId:   transferOptionalIdToDomain(req.ProjectId),
+		Status:      domain.Status(req.Status),
 		// Tags:        []tagDomain.Tag{},
 	}
 	// for _, task_id := range req.Tags {
 	// 	data.Tags = append(data.Tags, tagDomain.Tag{ID: task_id})
 	// }
+	transferDueToDomain(req.Due, data)
+	return data
+}
+
+func createErrorCode(err error) codes.Code {
+	if errors.Is(err, domain.ErrTagNotExists) || errors.Is(err, domain.ErrParentNotExists) || errors.Is(err, domain.ErrTaskTooDeep) || errors.Is(err, domain.ErrProjectNotExists) {
+		return codes.InvalidArgument
+	}
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return codes.PermissionDenied
+	}
+	if errors.Is(err, domain.ErrTaskExists) {
+		return codes.AlreadyExists
+	}
+	return codes.Unknown
+}
+
+func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*api.BasicTask, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
 
-	new_task, err := serverInstance.repo.Create(ctx, creator_id, data)
+	if err := serverInstance.checkUser(ctx, req.NewTaskInfo.AssigneeId); err != nil {
+		return nil, err
+	}
+
+	data := transferBasicTaskToDomain(req.NewTaskInfo)
+	data.Version = req.Version
+	new_task, err := serverInstance.repo.Update(ctx, req.Id, getUserId(ctx), data, req.TagsAdded, req.TagsDeleted, req.Force)
 
 	if err != nil {
 		log.Println(err.Error())
-		if errors.Is(err, domain.ErrTagNotExists) {
-			return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+		if errors.Is(err, domain.ErrTaskVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, map[int32]int32{req.Id: req.Version})
 		}
-		return nil, grpc_status.Error(codes.Unknown, err.Error())
+		return nil, grpc_status.Error(updateErrorCode(err), err.Error())
 	}
 
 	return transferDomainToBasicTask(new_task), nil
 }
 
-func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*api.BasicTask, error) {
-	data := transferBasicTaskToDomain(req.NewTaskInfo)
-	new_task, err := serverInstance.repo.Update(ctx, req.Id, data, req.TagsAdded, req.TagsDeleted)
+func updateErrorCode(err error) codes.Code {
+	if errors.Is(err, domain.ErrTaskNotExists) {
+		return codes.NotFound
+	}
+	if errors.Is(err, domain.ErrPermissionDenied) {
+		return codes.PermissionDenied
+	}
+	if errors.Is(err, domain.ErrTaskVersionMismatch) {
+		return codes.Aborted
+	}
+	if errors.Is(err, domain.ErrTaskHasOpenChildren) || errors.Is(err, domain.ErrStatusTransition) || errors.Is(err, domain.ErrTaskBlocked) {
+		return codes.FailedPrecondition
+	}
+	if errors.Is(err, domain.ErrTagNotExists) || errors.Is(err, domain.ErrProjectNotExists) {
+		return codes.NotFound
+	}
+	if errors.Is(err, domain.ErrTaskExists) {
+		return codes.AlreadyExists
+	}
+	return codes.Unknown
+}
+
+func (serverInstance *server) MoveTask(ctx context.Context, req *api.MoveTaskReq) (*api.BasicTask, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	task, err := serverInstance.repo.Move(ctx, req.Id, getUserId(ctx), transferOptionalIdToDomain(req.ParentId), req.BeforeId, req.AfterId, req.Version)
 
 	if err != nil {
 		log.Println(err.Error())
-		if errors.Is(err, domain.ErrTagNotExists) {
+		if errors.Is(err, domain.ErrTaskNotExists) {
 			return nil, grpc_status.Error(codes.NotFound, err.Error())
 		}
-		if errors.Is(err, domain.ErrTaskExists) {
-			return nil, grpc_status.Error(codes.AlreadyExists, err.Error())
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrParentNotExists) || errors.Is(err, domain.ErrTaskCycle) || errors.Is(err, domain.ErrTaskTooDeep) || errors.Is(err, domain.ErrRankNeighbour) {
+			return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, domain.ErrTaskVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, map[int32]int32{req.Id: req.Version})
 		}
 		return nil, grpc_status.Error(codes.Unknown, err.Error())
 	}
 
-	return transferDomainToBasicTask(new_task), nil
+	return transferDomainToBasicTask(task), nil
 }
 
+// Tasks go to trash, their attachments are kept until they are purged
 func (serverInstance *server) DeleteMultiple(ctx context.Context, req *api.DeleteMultipleReq) (*emptypb.Empty, error) {
-	err := serverInstance.repo.Delete(ctx, req.TasksId)
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err := serverInstance.repo.Delete(ctx, getUserId(ctx), req.TasksId, req.Versions)
 
 	if err != nil {
-		if errors.Is(err, domain.ErrTagNotExists) {
+		if errors.Is(err, domain.ErrTagNotExists) || errors.Is(err, domain.ErrTaskNotExists) {
 			return nil, grpc_status.Error(codes.NotFound, err.Error())
 		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrTaskVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, req.Versions)
+		}
 		return nil, grpc_status.Error(codes.Unknown, err.Error())
 	}
 
@@ -181,10 +556,9 @@ func (serverInstance *server) DeleteMultiple(ctx context.Context, req *api.Delet
 }
 
 func (serverInstance *server) DeleteAll(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
-	// TODO: Get creator id
-	// var creator_id int32 = 1
+	creator_id := getUserId(ctx)
 
-	err := serverInstance.repo.Delete(ctx, []int32{})
+	err := serverInstance.repo.Delete(ctx, creator_id, []int32{}, nil)
 
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotExists) {
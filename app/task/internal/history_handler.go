@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToHistoryEntry(in *domain.HistoryEntry) *api.HistoryEntry {
+	entry := &api.HistoryEntry{
+		Id:          in.ID,
+		TaskId:      in.TaskId,
+		ActorId:     in.ActorId,
+		Action:      api.HistoryAction(api.HistoryAction_value[string(in.Action)]),
+		Changes:     []*api.FieldChange{},
+		CreatedTime: timestamppb.New(in.CreatedAt),
+	}
+
+	// Fields are sorted, so output does not depend on order of map
+	fields := []string{}
+	for field := range in.Changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		change := in.Changes[field]
+		before, _ := structpb.NewValue(change.Before)
+		after, _ := structpb.NewValue(change.After)
+		entry.Changes = append(entry.Changes, &api.FieldChange{
+			Field:  field,
+			Before: before,
+			After:  after,
+		})
+	}
+	return entry
+}
+
+func (serverInstance *server) GetHistory(ctx context.Context, req *api.GetHistoryReq) (*api.ListHistory, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entries, err := serverInstance.historyRepo.FetchByTask(ctx, req.TaskId, getUserId(ctx), req.PageToken, req.PageSize)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	history_rs := &api.ListHistory{Entries: []*api.HistoryEntry{}}
+	for _, entry := range entries {
+		history_rs.Entries = append(history_rs.Entries, transferDomainToHistoryEntry(&entry))
+	}
+
+	return history_rs, nil
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	response_handler "todo-go-grpc/app/response_handler"
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToProject(in *domain.Project) *api.Project {
+	return &api.Project{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		Color:       in.Color,
+		Archived:    in.Archived,
+		OwnerId:     in.OwnerId,
+		CreatedTime: timestamppb.New(in.CreatedAt),
+		Version:     in.Version,
+		OpenCount:   in.OpenCount,
+		DoneCount:   in.DoneCount,
+	}
+}
+
+func transferProjectToDomain(in *api.Project) *domain.Project {
+	return &domain.Project{
+		ID:          in.Id,
+		Name:        in.Name,
+		Description: in.Description,
+		Color:       in.Color,
+		Archived:    in.Archived,
+		OwnerId:     in.OwnerId,
+		CreatedAt:   in.CreatedTime.AsTime(),
+		Version:     in.Version,
+	}
+}
+
+// Attach current state of project when its version is different from expected
+func (serverInstance *server) responseProjectVersionMismatch(ctx context.Context, err error, id int32) error {
+	project, get_err := serverInstance.projectRepo.GetByID(ctx, id, getUserId(ctx))
+	if get_err != nil {
+		return response_handler.ResponseErrorAborted(err)
+	}
+
+	return response_handler.ResponseErrorAborted(err, transferDomainToProject(project))
+}
+
+func (serverInstance *server) CreateProject(ctx context.Context, req *api.CreateProjectReq) (*api.Project, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	project, err := serverInstance.projectRepo.Create(ctx, &domain.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+		OwnerId:     getUserId(ctx),
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToProject(project), nil
+}
+
+func (serverInstance *server) GetProject(ctx context.Context, req *api.GetProjectReq) (*api.Project, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	project, err := serverInstance.projectRepo.GetByID(ctx, req.Id, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrProjectNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToProject(project), nil
+}
+
+func (serverInstance *server) ListProjects(ctx context.Context, req *api.ListProjectsReq) (*api.ListProject, error) {
+	projects, err := serverInstance.projectRepo.Fetch(ctx, getUserId(ctx), req.IncludeArchived)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	projects_rs := &api.ListProject{Projects: []*api.Project{}}
+	for _, project := range projects {
+		projects_rs.Projects = append(projects_rs.Projects, transferDomainToProject(&project))
+	}
+
+	return projects_rs, nil
+}
+
+func (serverInstance *server) UpdateProject(ctx context.Context, req *api.UpdateProjectReq) (*api.Project, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data := transferProjectToDomain(req.NewProjectInfo)
+	data.Version = req.Version
+	project, err := serverInstance.projectRepo.Update(ctx, req.Id, getUserId(ctx), data)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrProjectNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrProjectVersionMismatch) {
+			return nil, serverInstance.responseProjectVersionMismatch(ctx, err, req.Id)
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToProject(project), nil
+}
+
+func (serverInstance *server) DeleteProject(ctx context.Context, req *api.DeleteProjectReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.projectRepo.Delete(ctx, req.Id, getUserId(ctx), req.Version); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrProjectNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrProjectVersionMismatch) {
+			return nil, serverInstance.responseProjectVersionMismatch(ctx, err, req.Id)
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
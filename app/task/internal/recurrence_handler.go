@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToSeries(in *domain.Series) *api.Series {
+	if in == nil {
+		return nil
+	}
+
+	series := &api.Series{
+		Id: in.ID,
+		Recurrence: &api.Recurrence{
+			Rule:   in.Rule,
+			Anchor: api.RecurrenceAnchor(in.Anchor),
+		},
+		Occurrences: in.Occurrences,
+	}
+	if in.StoppedAt != nil {
+		series.StoppedTime = timestamppb.New(*in.StoppedAt)
+	}
+	return series
+}
+
+// Recurrence is valid here, rule is stored in its normalized form
+func transferRecurrenceToSeries(in *api.Recurrence) *domain.Series {
+	if in == nil {
+		return nil
+	}
+
+	recurrence, _ := domain.ParseRecurrence(in.Rule)
+	return &domain.Series{
+		Rule:   recurrence.String(),
+		Anchor: domain.RecurrenceAnchor(in.Anchor),
+	}
+}
+
+func (serverInstance *server) SkipOccurrence(ctx context.Context, req *api.SkipOccurrenceReq) (*api.SkipOccurrenceRes, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	next, err := serverInstance.repo.Skip(ctx, req.Id, getUserId(ctx), req.Version)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) || errors.Is(err, domain.ErrSeriesNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrTaskNotRecurring) {
+			return nil, grpc_status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrTaskVersionMismatch) {
+			return nil, serverInstance.responseVersionMismatch(ctx, err, map[int32]int32{req.Id: req.Version})
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	if next == nil {
+		return &api.SkipOccurrenceRes{}, nil
+	}
+	return &api.SkipOccurrenceRes{Next: transferDomainToBasicTask(next)}, nil
+}
+
+func (serverInstance *server) UpdateSeries(ctx context.Context, req *api.UpdateSeriesReq) (*api.Series, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data := transferRecurrenceToSeries(req.Recurrence)
+	series, err := serverInstance.repo.UpdateSeries(ctx, req.SeriesId, getUserId(ctx), data.Rule, data.Anchor)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrSeriesNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToSeries(series), nil
+}
+
+func (serverInstance *server) StopSeries(ctx context.Context, req *api.StopSeriesReq) (*api.Series, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	series, err := serverInstance.repo.StopSeries(ctx, req.SeriesId, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrSeriesNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToSeries(series), nil
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToReminder(in *domain.Reminder) *api.Reminder {
+	reminder := &api.Reminder{
+		Id:     in.ID,
+		TaskId: in.TaskId,
+	}
+	if in.RemindAt != nil {
+		reminder.RemindTime = timestamppb.New(*in.RemindAt)
+	} else {
+		reminder.BeforeDue = durationpb.New(time.Duration(in.OffsetSeconds) * time.Second)
+	}
+	if in.SnoozedUntil != nil {
+		reminder.SnoozedUntil = timestamppb.New(*in.SnoozedUntil)
+	}
+	if in.FiredAt != nil {
+		reminder.FiredTime = timestamppb.New(*in.FiredAt)
+	}
+	return reminder
+}
+
+func (serverInstance *server) AddReminder(ctx context.Context, req *api.AddReminderReq) (*api.Reminder, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, req.TaskId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	data := &domain.Reminder{TaskId: req.TaskId}
+	if req.RemindTime != nil {
+		remind_at := req.RemindTime.AsTime()
+		data.RemindAt = &remind_at
+	} else {
+		data.OffsetSeconds = int64(req.BeforeDue.AsDuration() / time.Second)
+	}
+
+	reminder, err := serverInstance.reminderRepo.Create(ctx, data)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToReminder(reminder), nil
+}
+
+func (serverInstance *server) ListReminders(ctx context.Context, req *api.ListRemindersReq) (*api.ListReminder, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkPermission(ctx, req.TaskId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	reminders, err := serverInstance.reminderRepo.FetchByTask(ctx, req.TaskId)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	reminders_rs := &api.ListReminder{Reminders: []*api.Reminder{}}
+	for _, reminder := range reminders {
+		reminders_rs.Reminders = append(reminders_rs.Reminders, transferDomainToReminder(&reminder))
+	}
+
+	return reminders_rs, nil
+}
+
+func (serverInstance *server) SnoozeReminder(ctx context.Context, req *api.SnoozeReminderReq) (*api.Reminder, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	reminder, err := serverInstance.reminderRepo.Snooze(ctx, req.Id, getUserId(ctx), time.Now().Add(req.Snooze.AsDuration()))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrReminderNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToReminder(reminder), nil
+}
+
+func (serverInstance *server) DeleteReminder(ctx context.Context, req *api.DeleteReminderReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.reminderRepo.Delete(ctx, req.Id, getUserId(ctx)); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrReminderNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
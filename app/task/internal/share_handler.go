@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToCollaborator(in *domain.Share) *api.Collaborator {
+	return &api.Collaborator{
+		UserId:     in.UserId,
+		Permission: api.Permission(in.Permission),
+		SharedTime: timestamppb.New(in.CreatedAt),
+	}
+}
+
+func (serverInstance *server) Share(ctx context.Context, req *api.ShareReq) (*api.Collaborator, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.checkUser(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	share, err := serverInstance.shareRepo.Create(ctx, getUserId(ctx), &domain.Share{
+		TaskId:     req.TaskId,
+		UserId:     req.UserId,
+		Permission: domain.Permission(req.Permission),
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToCollaborator(share), nil
+}
+
+func (serverInstance *server) Unshare(ctx context.Context, req *api.UnshareReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.shareRepo.Delete(ctx, req.TaskId, getUserId(ctx), req.UserId); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) || errors.Is(err, domain.ErrShareNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (serverInstance *server) ListCollaborators(ctx context.Context, req *api.ListCollaboratorsReq) (*api.ListCollaborator, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	shares, err := serverInstance.shareRepo.FetchByTask(ctx, req.TaskId, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	collaborators_rs := &api.ListCollaborator{Collaborators: []*api.Collaborator{}}
+	for _, share := range shares {
+		collaborators_rs.Collaborators = append(collaborators_rs.Collaborators, transferDomainToCollaborator(&share))
+	}
+
+	return collaborators_rs, nil
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToTemplate(in *domain.Template) *api.Template {
+	template := &api.Template{
+		Id:          in.ID,
+		Name:        in.Name,
+		Items:       []*api.TemplateItem{},
+		CreatedTime: timestamppb.New(in.CreatedAt),
+	}
+	for _, item := range in.Items {
+		template_item := &api.TemplateItem{
+			Id:          item.ID,
+			ParentId:    transferOptionalIdToProto(item.ParentId),
+			Name:        item.Name,
+			Description: item.Description,
+			Priority:    api.Priority(item.Priority),
+			TagsId:      []int32{},
+			DueAllDay:   item.DueAllDay,
+		}
+		if item.DueOffsetSeconds != nil {
+			template_item.DueOffset = transferSecondsToProto(*item.DueOffsetSeconds)
+		}
+		for _, tag := range item.Tags {
+			template_item.TagsId = append(template_item.TagsId, tag.ID)
+		}
+		template.Items = append(template.Items, template_item)
+	}
+	return template
+}
+
+func (serverInstance *server) CreateTemplate(ctx context.Context, req *api.CreateTemplateReq) (*api.Template, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	template, err := serverInstance.templateRepo.Create(ctx, getUserId(ctx), req.TaskId, req.Name)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrTemplateExists) {
+			return nil, grpc_status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToTemplate(template), nil
+}
+
+func (serverInstance *server) ListTemplates(ctx context.Context, req *emptypb.Empty) (*api.ListTemplate, error) {
+	templates, err := serverInstance.templateRepo.Fetch(ctx, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	templates_rs := &api.ListTemplate{Templates: []*api.Template{}}
+	for _, template := range templates {
+		templates_rs.Templates = append(templates_rs.Templates, transferDomainToTemplate(&template))
+	}
+
+	return templates_rs, nil
+}
+
+func (serverInstance *server) DeleteTemplate(ctx context.Context, req *api.DeleteTemplateReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.templateRepo.Delete(ctx, req.Id, getUserId(ctx)); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTemplateNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (serverInstance *server) InstantiateTemplate(ctx context.Context, req *api.InstantiateTemplateReq) (*api.Task, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	location, _ := time.LoadLocation(req.Timezone)
+	user_id := getUserId(ctx)
+	task, err := serverInstance.templateRepo.Instantiate(ctx, req.Id, user_id, transferOptionalIdToDomain(req.ParentId), transferOptionalIdToDomain(req.ProjectId), time.Now().In(location))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTemplateNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrParentNotExists) || errors.Is(err, domain.ErrTaskTooDeep) || errors.Is(err, domain.ErrProjectNotExists) {
+			return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	// Read created task again with its children and counts
+	if task, err = serverInstance.repo.GetByID(ctx, task.ID, user_id); err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToTask(task), nil
+}
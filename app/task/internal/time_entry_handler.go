@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func transferDomainToTimeEntry(in *domain.TimeEntry) *api.TimeEntry {
+	entry := &api.TimeEntry{
+		Id:        in.ID,
+		TaskId:    in.TaskId,
+		UserId:    in.UserId,
+		StartTime: timestamppb.New(in.StartedAt),
+		Duration:  durationpb.New(in.Duration(time.Now())),
+		Note:      in.Note,
+	}
+	if in.EndedAt != nil {
+		entry.EndTime = timestamppb.New(*in.EndedAt)
+	}
+	return entry
+}
+
+func transferSecondsToProto(seconds int64) *durationpb.Duration {
+	return durationpb.New(time.Duration(seconds) * time.Second)
+}
+
+func (serverInstance *server) StartTimer(ctx context.Context, req *api.StartTimerReq) (*api.TimeEntry, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entry, err := serverInstance.timeEntryRepo.Start(ctx, &domain.TimeEntry{
+		TaskId:    req.TaskId,
+		UserId:    getUserId(ctx),
+		StartedAt: time.Now(),
+		Note:      req.Note,
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, domain.ErrTimerRunning) {
+			return nil, grpc_status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToTimeEntry(entry), nil
+}
+
+func (serverInstance *server) StopTimer(ctx context.Context, req *api.StopTimerReq) (*api.TimeEntry, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entry, err := serverInstance.timeEntryRepo.Stop(ctx, getUserId(ctx), time.Now())
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTimerNotRunning) {
+			return nil, grpc_status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToTimeEntry(entry), nil
+}
+
+func (serverInstance *server) AddTimeEntry(ctx context.Context, req *api.AddTimeEntryReq) (*api.TimeEntry, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ended_at := req.EndTime.AsTime()
+	entry, err := serverInstance.timeEntryRepo.Create(ctx, &domain.TimeEntry{
+		TaskId:    req.TaskId,
+		UserId:    getUserId(ctx),
+		StartedAt: req.StartTime.AsTime(),
+		EndedAt:   &ended_at,
+		Note:      req.Note,
+	})
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return transferDomainToTimeEntry(entry), nil
+}
+
+func (serverInstance *server) TimeReport(ctx context.Context, req *api.TimeReportReq) (*api.TimeReportRes, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	location, _ := time.LoadLocation(req.Timezone)
+	report, err := serverInstance.timeEntryRepo.Report(ctx, getUserId(ctx), req.From.AsTime(), req.To.AsTime(), location)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	report_rs := &api.TimeReportRes{
+		Total:  transferSecondsToProto(report.TotalSeconds),
+		ByDay:  []*api.DayTime{},
+		ByTask: []*api.TaskTime{},
+		ByTag:  []*api.TagTime{},
+	}
+	for _, day := range report.ByDay {
+		report_rs.ByDay = append(report_rs.ByDay, &api.DayTime{
+			Day:      day.Day.Format("2006-01-02"),
+			Duration: transferSecondsToProto(day.Seconds),
+		})
+	}
+	for _, task := range report.ByTask {
+		report_rs.ByTask = append(report_rs.ByTask, &api.TaskTime{
+			TaskId:   task.TaskId,
+			Name:     task.Name,
+			Duration: transferSecondsToProto(task.Seconds),
+		})
+	}
+	for _, tag := range report.ByTag {
+		report_rs.ByTag = append(report_rs.ByTag, &api.TagTime{
+			TagId:    tag.TagId,
+			Value:    tag.Value,
+			Duration: transferSecondsToProto(tag.Seconds),
+		})
+	}
+
+	return report_rs, nil
+}
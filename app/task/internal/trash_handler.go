@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (serverInstance *server) ListTrash(ctx context.Context, req *api.ListTrashReq) (*api.ListTask, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	tasks_domain, err := serverInstance.repo.FetchTrash(ctx, getUserId(ctx), req.PageToken, req.PageSize)
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	tasks_rs := &api.ListTask{Tasks: []*api.Task{}}
+	for _, task := range tasks_domain {
+		tasks_rs.Tasks = append(tasks_rs.Tasks, transferDomainToTask(&task))
+	}
+
+	return tasks_rs, nil
+}
+
+func (serverInstance *server) Restore(ctx context.Context, req *api.RestoreReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := serverInstance.repo.Restore(ctx, getUserId(ctx), req.TasksId); err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (serverInstance *server) Purge(ctx context.Context, req *api.PurgeReq) (*emptypb.Empty, error) {
+	if err := req.Valid(); err != nil {
+		return nil, grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ids := req.TasksId
+	if req.All {
+		ids = nil
+	}
+	keys, err := serverInstance.repo.Purge(ctx, getUserId(ctx), ids)
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrTaskNotExists) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	serverInstance.deleteBlobs(ctx, keys)
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Revert the last update or delete of caller
+func (serverInstance *server) Undo(ctx context.Context, req *emptypb.Empty) (*api.UndoRes, error) {
+	ids, err := serverInstance.repo.Undo(ctx, getUserId(ctx))
+
+	if err != nil {
+		log.Println(err.Error())
+		if errors.Is(err, domain.ErrNothingToUndo) {
+			return nil, grpc_status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, domain.ErrUndoConflict) {
+			return nil, grpc_status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			return nil, grpc_status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, grpc_status.Error(codes.Unknown, err.Error())
+	}
+
+	return &api.UndoRes{TasksId: ids}, nil
+}
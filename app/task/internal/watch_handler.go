@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"log"
+	"time"
+
+	api "todo-go-grpc/app/task/api"
+	domain "todo-go-grpc/app/task/domain"
+
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+)
+
+const (
+	// Number of history entries read at once while watcher catches up
+	watchBatchSize = 100
+	// Entries written while an older transaction is still running are read again after this wait
+	watchWaitInterval = time.Second
+)
+
+var watchEventTypes = map[domain.HistoryAction]api.WatchEventType{
+	domain.HistoryCreated:  api.WatchEventType_TASK_CREATED,
+	domain.HistoryRestored: api.WatchEventType_TASK_CREATED,
+	domain.HistoryUpdated:  api.WatchEventType_TASK_UPDATED,
+	domain.HistoryMoved:    api.WatchEventType_TASK_UPDATED,
+	domain.HistoryDeleted:  api.WatchEventType_TASK_DELETED,
+	domain.HistoryPurged:   api.WatchEventType_TASK_PURGED,
+}
+
+func transferHistoryEntryToWatchEvent(in *domain.HistoryEntry) *api.WatchEvent {
+	event := &api.WatchEvent{
+		Type:        watchEventTypes[in.Action],
+		Entry:       transferDomainToHistoryEntry(in),
+		ResumeToken: domain.HistoryCursor{TxId: in.TxId, Id: in.ID}.String(),
+	}
+	if in.Task.ID != 0 {
+		event.Task = transferDomainToBasicTask(&in.Task)
+	}
+	return event
+}
+
+// Events are read from history after every signal of broker, so a missed signal only delays them until the next one
+func (serverInstance *server) Watch(req *api.WatchReq, stream api.TaskHandler_WatchServer) error {
+	if err := req.Valid(); err != nil {
+		return grpc_status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	user_id := getUserId(ctx)
+
+	// Subscribe before the first read, so no change falls between them
+	signal, cancel := serverInstance.broker.Subscribe()
+	defer cancel()
+
+	var after domain.HistoryCursor
+	if req.ResumeToken != "" {
+		after, _ = domain.ParseHistoryCursor(req.ResumeToken)
+	} else {
+		last, err := serverInstance.historyRepo.LastEventCursor(ctx)
+		if err != nil {
+			log.Println(err.Error())
+			return grpc_status.Error(codes.Unknown, err.Error())
+		}
+		after = last
+	}
+
+	for {
+		entries, waiting, err := serverInstance.historyRepo.FetchEvents(ctx, user_id, after, watchBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Println(err.Error())
+			return grpc_status.Error(codes.Unknown, err.Error())
+		}
+
+		for i := range entries {
+			if err := stream.Send(transferHistoryEntryToWatchEvent(&entries[i])); err != nil {
+				return err
+			}
+			after = domain.HistoryCursor{TxId: entries[i].TxId, Id: entries[i].ID}
+		}
+		// Full batch means more events are waiting
+		if len(entries) == watchBatchSize {
+			continue
+		}
+
+		// Transaction which ends without writing history sends no signal, so waiting entries are polled
+		var wait <-chan time.Time
+		if waiting {
+			wait = time.After(watchWaitInterval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signal:
+		case <-wait:
+		}
+	}
+}
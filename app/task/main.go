@@ -1,23 +1,70 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"os"
 	"strconv"
+	"time"
+	"todo-go-grpc/app/auth"
 	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/idempotency"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
+	"todo-go-grpc/app/task/blobstore"
+	domain "todo-go-grpc/app/task/domain"
 	service "todo-go-grpc/app/task/internal"
+	"todo-go-grpc/app/task/reminder"
+	repository "todo-go-grpc/app/task/repository"
 	repo "todo-go-grpc/app/task/repository/postgre"
+	"todo-go-grpc/app/task/trash"
+	"todo-go-grpc/app/task/userservice"
+	"todo-go-grpc/app/task/watch"
 )
 
 const (
 	port int = 8082
+
+	userServiceAddress string = "localhost:8081"
+
+	maxTaskDepth       int32                     = 5
+	completeParentRule domain.CompleteParentRule = domain.CompleteParentBlock
+	undoWindow         time.Duration             = time.Minute
+	searchLanguage     string                    = "english"
+	maxBatchItems      int                       = 500
+
+	reminderInterval       time.Duration = 10 * time.Second
+	reminderBatchSize      int           = 100
+	reminderWebhookTimeout time.Duration = 5 * time.Second
+
+	maxAttachmentSize     int64  = 25 << 20
+	maxUserAttachmentSize int64  = 1 << 30
+	attachmentDir         string = "attachments"
+
+	trashRetention      time.Duration = 30 * 24 * time.Hour
+	trashPurgeInterval  time.Duration = time.Hour
+	trashPurgeBatchSize int           = 100
+
+	watchRetryInterval time.Duration = 5 * time.Second
+
+	idempotencyTTL           time.Duration = 24 * time.Hour
+	idempotencyLease         time.Duration = time.Minute
+	idempotencyPurgeInterval time.Duration = time.Hour
 )
 
 func main() {
-	server := grpc.NewServer()
+	// Every call carries token signed by user service with AUTH_SECRET
+	secret := os.Getenv("AUTH_SECRET")
+	if secret == "" {
+		log.Fatalf("AUTH_SECRET must be set")
+	}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(auth.UnaryInterceptor([]byte(secret))),
+		grpc.StreamInterceptor(auth.StreamInterceptor([]byte(secret))),
+	)
 
 	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
 
@@ -27,8 +74,71 @@ func main() {
 
 	db := dbservice.Init()
 
-	taskRepository := repo.NewTaskRepository(*db)
-	service.RegisterGrpc(server, taskRepository)
+	// Watchers of every instance are signalled through Postgres unless WATCH_BROKER is memory
+	broker := watch.NewPostgresBroker(*db, watchRetryInterval)
+	if os.Getenv("WATCH_BROKER") == "memory" {
+		broker = watch.NewMemoryBroker()
+	}
+	go broker.Start(context.Background())
+
+	taskConfig := repository.Config{
+		MaxDepth:           maxTaskDepth,
+		CompleteParentRule: completeParentRule,
+		UndoWindow:         undoWindow,
+		SearchLanguage:     searchLanguage,
+		Broker:             broker,
+	}
+	if err := repo.CreateSearchIndex(*db, searchLanguage); err != nil {
+		log.Fatalf("Create search index error:\n%v", err)
+	}
+	taskRepository := repo.NewTaskRepository(*db, taskConfig)
+	reminderRepository := repo.NewReminderRepository(*db)
+	commentRepository := repo.NewCommentRepository(*db)
+	attachmentRepository := repo.NewAttachmentRepository(*db)
+	shareRepository := repo.NewShareRepository(*db)
+	projectRepository := repo.NewProjectRepository(*db)
+	dependencyRepository := repo.NewDependencyRepository(*db)
+	timeEntryRepository := repo.NewTimeEntryRepository(*db)
+	templateRepository := repo.NewTemplateRepository(*db, taskConfig)
+	historyRepository := repo.NewHistoryRepository(*db)
+	idempotencyStore := idempotency.NewStore(*db, idempotencyTTL, idempotencyLease, idempotencyPurgeInterval)
+
+	// Attachments are stored in S3 compatible bucket when its endpoint is set, otherwise in local directory
+	blobStore := blobstore.NewLocalStore(attachmentDir)
+	if endpoint := os.Getenv("ATTACHMENT_S3_ENDPOINT"); endpoint != "" {
+		blobStore = blobstore.NewS3Store(endpoint, os.Getenv("ATTACHMENT_S3_BUCKET"), os.Getenv("ATTACHMENT_S3_REGION"),
+			os.Getenv("ATTACHMENT_S3_ACCESS_KEY"), os.Getenv("ATTACHMENT_S3_SECRET_KEY"))
+	}
+
+	// Address of user service can be changed by USER_SERVICE_ADDRESS
+	address := userServiceAddress
+	if value := os.Getenv("USER_SERVICE_ADDRESS"); value != "" {
+		address = value
+	}
+	userConn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Dial user service error:\n%v", err)
+	}
+	defer userConn.Close()
+
+	service.RegisterGrpc(server, service.Config{
+		MaxAttachmentSize:     maxAttachmentSize,
+		MaxUserAttachmentSize: maxUserAttachmentSize,
+		MaxBatchItems:         maxBatchItems,
+	}, taskRepository, reminderRepository, commentRepository, attachmentRepository, shareRepository, projectRepository, dependencyRepository, timeEntryRepository, templateRepository, historyRepository, idempotencyStore, broker, blobStore, userservice.NewGrpcUserService(userConn))
+
+	// Reminders are sent to webhook when its url is set, otherwise they are logged
+	notifier := reminder.NewLogNotifier()
+	if url := os.Getenv("REMINDER_WEBHOOK_URL"); url != "" {
+		notifier = reminder.NewWebhookNotifier(url, reminderWebhookTimeout)
+	}
+	dispatcher := reminder.NewDispatcher(reminderRepository, notifier, reminderInterval, reminderBatchSize)
+	go dispatcher.Start(context.Background())
+
+	purger := trash.NewPurger(taskRepository, blobStore, trashRetention, trashPurgeInterval, trashPurgeBatchSize)
+	go purger.Start(context.Background())
+
+	go idempotencyStore.Start(context.Background())
 
 	log.Printf("Task service start on port %v", port)
 	if err := server.Serve(listener); err != nil {
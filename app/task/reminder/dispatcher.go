@@ -0,0 +1,64 @@
+package reminder
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+// Dispatcher checks due reminders every interval and sends them through notifier
+type Dispatcher struct {
+	repo      repository.ReminderRepository
+	notifier  Notifier
+	interval  time.Duration
+	batchSize int
+}
+
+func NewDispatcher(repo repository.ReminderRepository, notifier Notifier, interval time.Duration, batchSize int) *Dispatcher {
+	return &Dispatcher{
+		repo:      repo,
+		notifier:  notifier,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run until ctx is done
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) {
+	for {
+		fired, err := d.repo.FireDue(ctx, time.Now(), d.batchSize, func(reminder domain.Reminder) error {
+			if err := d.notifier.Notify(ctx, reminder); err != nil {
+				log.Printf("Notify reminder %v error: %v", reminder.ID, err)
+				return err
+			}
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("Dispatch reminders error: %v", err)
+			return
+		}
+
+		// Batch is not full, nothing is left to fire
+		if fired < d.batchSize {
+			return
+		}
+	}
+}
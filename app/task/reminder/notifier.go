@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"todo-go-grpc/app/task/domain"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, reminder domain.Reminder) error
+}
+
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	log.Printf("Reminder %v of task %v: %v", reminder.ID, reminder.TaskId, reminder.Task.Name)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookPayload struct {
+	ReminderId int32      `json:"reminder_id"`
+	TaskId     int32      `json:"task_id"`
+	TaskName   string     `json:"task_name"`
+	DueAt      *time.Time `json:"due_at,omitempty"`
+	FiredAt    time.Time  `json:"fired_at"`
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	body, err := json.Marshal(webhookPayload{
+		ReminderId: reminder.ID,
+		TaskId:     reminder.TaskId,
+		TaskName:   reminder.Task.Name,
+		DueAt:      reminder.Task.DueAt,
+		FiredAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("Webhook responded with status %v", res.StatusCode)
+	}
+	return nil
+}
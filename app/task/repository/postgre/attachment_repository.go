@@ -0,0 +1,102 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type attachmentRepository struct {
+	Conn dbservice.Database
+}
+
+func NewAttachmentRepository(conn dbservice.Database) repository.AttachmentRepository {
+	return &attachmentRepository{
+		Conn: conn,
+	}
+}
+
+func (a *attachmentRepository) GetByID(ctx context.Context, id int32) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	if err := a.Conn.Db.First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAttachmentNotExists
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (a *attachmentRepository) FetchByTask(ctx context.Context, task_id int32) ([]domain.Attachment, error) {
+	var attachments []domain.Attachment
+	if err := a.Conn.Db.Where("task_id = ?", task_id).Order("id asc").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (a *attachmentRepository) TotalSizeByUser(ctx context.Context, user_id int32) (int64, error) {
+	return totalSizeByUser(a.Conn.Db, user_id)
+}
+
+func totalSizeByUser(tx *gorm.DB, user_id int32) (int64, error) {
+	var total int64
+	err := tx.Model(&domain.Attachment{}).Where("uploader_id = ?", user_id).Select("COALESCE(SUM(size), 0)").Scan(&total).Error
+	return total, err
+}
+
+func (a *attachmentRepository) Create(ctx context.Context, info *domain.Attachment, max_user_size int64) (*domain.Attachment, error) {
+	err := a.Conn.Db.Transaction(func(tx *gorm.DB) error {
+		// Serialize uploads of one user, so concurrent uploads can not pass quota together
+		if err := lock(tx, attachmentLock, strconv.Itoa(int(info.UploaderId))); err != nil {
+			return err
+		}
+
+		total, err := totalSizeByUser(tx, info.UploaderId)
+		if err != nil {
+			return err
+		}
+		if total+info.Size > max_user_size {
+			return domain.ErrAttachmentQuota
+		}
+
+		if err := tx.Omit("Task").Create(info).Error; err != nil {
+			if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+				if pgError.Code == "23503" {
+					return domain.ErrTaskNotExists
+				}
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (a *attachmentRepository) Delete(ctx context.Context, id int32) (*domain.Attachment, error) {
+	var attachment domain.Attachment
+	result := a.Conn.Db.Clauses(clause.Returning{}).Where("id = ?", id).Delete(&attachment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrAttachmentNotExists
+	}
+
+	return &attachment, nil
+}
@@ -0,0 +1,191 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	tagDomain "todo-go-grpc/app/tag/domain"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+)
+
+// Items are checked one by one, valid tasks along with their tags and history are written in multi-row inserts
+func (t *taskRepository) BatchCreate(ctx context.Context, creator_id int32, infos []*domain.Task, atomic bool) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(infos))
+	err := t.transaction(func(tx *gorm.DB) error {
+		// Tags are checked at once, so a missing tag fails its own item instead of the whole insert
+		tags_id := []int32{}
+		for _, info := range infos {
+			prepareCreate(creator_id, info)
+			for _, tag := range info.Tags {
+				tags_id = append(tags_id, tag.ID)
+			}
+		}
+		tags_exist := map[int32]bool{}
+		if len(tags_id) != 0 {
+			var existing []int32
+			if err := tx.Model(&tagDomain.Tag{}).Where("id IN ?", tags_id).Pluck("id", &existing).Error; err != nil {
+				return err
+			}
+			for _, id := range existing {
+				tags_exist[id] = true
+			}
+		}
+
+		// External id is unique among tasks of creator, trashed ones included
+		external_ids := []string{}
+		for _, info := range infos {
+			if info.ExternalId != nil {
+				external_ids = append(external_ids, *info.ExternalId)
+			}
+		}
+		external_ids_taken := map[string]bool{}
+		if len(external_ids) != 0 {
+			var taken []string
+			if err := tx.Unscoped().Model(&domain.Task{}).Where("creator_id = ? AND external_id IN ?", creator_id, external_ids).Pluck("external_id", &taken).Error; err != nil {
+				return err
+			}
+			for _, external_id := range taken {
+				external_ids_taken[external_id] = true
+			}
+		}
+
+		valid := []*domain.Task{}
+		for i, info := range infos {
+			err := t.checkCreate(tx, creator_id, info)
+			for _, tag := range info.Tags {
+				if err == nil && !tags_exist[tag.ID] {
+					err = fmt.Errorf("%w: %d", domain.ErrTagNotExists, tag.ID)
+				}
+			}
+			// The first item with an external id takes it, later ones fail
+			if err == nil && info.ExternalId != nil {
+				if external_ids_taken[*info.ExternalId] {
+					err = fmt.Errorf("%w: external id %q", domain.ErrTaskExists, *info.ExternalId)
+				} else {
+					external_ids_taken[*info.ExternalId] = true
+				}
+			}
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Task = info
+			valid = append(valid, info)
+		}
+		if atomic && domain.BatchFailed(results) {
+			return domain.ErrBatchAborted
+		}
+		if len(valid) == 0 {
+			return nil
+		}
+
+		// Tasks of the same list are appended to it in the order of items
+		lists := map[string][]*domain.Task{}
+		keys := []string{}
+		for _, info := range valid {
+			key := rankList(tx, info.ParentId, info.ProjectId, creator_id).key
+			if _, ok := lists[key]; !ok {
+				keys = append(keys, key)
+			}
+			lists[key] = append(lists[key], info)
+		}
+		// Lists are locked in order of keys, so two batches appending to the same lists do not deadlock
+		sort.Strings(keys)
+		for _, key := range keys {
+			list_tasks := lists[key]
+			ranks, err := appendRanks(tx, rankList(tx, list_tasks[0].ParentId, list_tasks[0].ProjectId, creator_id), len(list_tasks))
+			if err != nil {
+				return err
+			}
+			for i, info := range list_tasks {
+				info.Rank = ranks[i]
+			}
+		}
+
+		// Every failure is found above, so rows are written in multi-row inserts
+		if err := tx.Omit("Tags").Create(&valid).Error; err != nil {
+			return err
+		}
+
+		task_tags := []map[string]any{}
+		entries := []domain.HistoryEntry{}
+		for _, info := range valid {
+			for _, tag := range info.Tags {
+				task_tags = append(task_tags, map[string]any{"task_id": info.ID, "tag_id": tag.ID})
+			}
+			entries = append(entries, domain.HistoryEntry{
+				TaskId:  info.ID,
+				ActorId: creator_id,
+				Action:  domain.HistoryCreated,
+				Changes: domain.DiffTasks(nil, info),
+			})
+		}
+		if len(task_tags) != 0 {
+			if err := tx.Table("task_tags").Create(&task_tags).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("Task").Create(&entries).Error
+	})
+
+	if errors.Is(err, domain.ErrBatchAborted) {
+		domain.AbortBatch(results)
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Every item is updated in its own savepoint, whole batch is undone at once
+func (t *taskRepository) BatchUpdate(ctx context.Context, user_id int32, updates []domain.TaskUpdate, atomic bool) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(updates))
+	err := t.transaction(func(tx *gorm.DB) error {
+		undo_tasks := domain.UndoTasks{}
+		undo_index := map[int32]int{}
+		for i, update := range updates {
+			// Failed item is rolled back to its savepoint, items before it are kept
+			results[i].Err = tx.Transaction(func(tx *gorm.DB) error {
+				task, item_undo_tasks, err := t.update(tx, update.Id, user_id, update.Info, update.TagsAdd, update.TagsRemove, update.Force)
+				if err != nil {
+					return err
+				}
+				results[i].Task = task
+				// Task changed twice in batch goes back to its state before the first change
+				for _, item_undo_task := range item_undo_tasks {
+					if index, ok := undo_index[item_undo_task.TaskId]; ok {
+						undo_tasks[index].Version = item_undo_task.Version
+						continue
+					}
+					undo_index[item_undo_task.TaskId] = len(undo_tasks)
+					undo_tasks = append(undo_tasks, item_undo_task)
+				}
+				return nil
+			})
+		}
+		if atomic && domain.BatchFailed(results) {
+			return domain.ErrBatchAborted
+		}
+		if len(undo_tasks) == 0 {
+			return nil
+		}
+
+		return recordUndo(tx, &domain.UndoOperation{UserId: user_id, Kind: domain.UndoUpdate, Tasks: undo_tasks})
+	})
+
+	if errors.Is(err, domain.ErrBatchAborted) {
+		domain.AbortBatch(results)
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
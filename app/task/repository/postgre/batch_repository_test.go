@@ -0,0 +1,109 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"testing"
+	tagDomain "todo-go-grpc/app/tag/domain"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+func TestBatchCreate(t *testing.T) {
+	repo, db := newTestRepository(t, repository.Config{})
+	ctx := context.Background()
+	creator_id := createTestUser(t, db, "creator")
+	tag := tagDomain.Tag{Value: "work"}
+	if err := db.Create(&tag).Error; err != nil {
+		t.Fatalf("create tag error: %v", err)
+	}
+	taken := "taken"
+	createTestTask(t, db, domain.Task{Name: "existing", CreatorId: creator_id, ExternalId: &taken})
+	external_id := func(value string) *string { return &value }
+
+	tests := []struct {
+		name   string
+		atomic bool
+		infos  []*domain.Task
+		want   []error
+	}{
+		{
+			name:   "atomic all valid",
+			atomic: true,
+			infos: []*domain.Task{
+				{Name: "a", Tags: []tagDomain.Tag{{ID: tag.ID}}},
+				{Name: "b", ExternalId: external_id("b")},
+			},
+			want: []error{nil, nil},
+		},
+		{
+			name:   "atomic missing tag",
+			atomic: true,
+			infos: []*domain.Task{
+				{Name: "c"},
+				{Name: "d", Tags: []tagDomain.Tag{{ID: tag.ID + 100}}},
+			},
+			want: []error{domain.ErrBatchAborted, domain.ErrTagNotExists},
+		},
+		{
+			name:   "atomic external id taken",
+			atomic: true,
+			infos: []*domain.Task{
+				{Name: "e", ExternalId: external_id(taken)},
+				{Name: "f"},
+			},
+			want: []error{domain.ErrTaskExists, domain.ErrBatchAborted},
+		},
+		{
+			name:   "best effort",
+			atomic: false,
+			infos: []*domain.Task{
+				{Name: "g", ExternalId: external_id("g")},
+				{Name: "h", ExternalId: external_id("g")},
+				{Name: "i", ExternalId: external_id(taken)},
+				{Name: "j", Tags: []tagDomain.Tag{{ID: tag.ID + 100}}},
+				{Name: "k", Tags: []tagDomain.Tag{{ID: tag.ID}}},
+			},
+			want: []error{nil, domain.ErrTaskExists, domain.ErrTaskExists, domain.ErrTagNotExists, nil},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tasks_before := countRows(t, db, "tasks", "creator_id = ?", creator_id)
+
+			results, err := repo.BatchCreate(ctx, creator_id, test.infos, test.atomic)
+			if err != nil {
+				t.Fatalf("BatchCreate() error: %v", err)
+			}
+			if len(results) != len(test.want) {
+				t.Fatalf("BatchCreate() returned %d results, want %d", len(results), len(test.want))
+			}
+
+			created := int64(0)
+			for i, result := range results {
+				if !errors.Is(result.Err, test.want[i]) {
+					t.Fatalf("BatchCreate() item %d error = %v, want %v", i, result.Err, test.want[i])
+				}
+				if result.Err != nil {
+					continue
+				}
+				created++
+				task := result.Task
+				if task == nil || task.ID == 0 || task.Rank == "" {
+					t.Fatalf("BatchCreate() item %d = %+v, want created task with rank", i, task)
+				}
+				if got := countRows(t, db, "history_entries", "task_id = ? AND action = ?", task.ID, domain.HistoryCreated); got != 1 {
+					t.Fatalf("history of item %d has %d created entries, want 1", i, got)
+				}
+				if got := countRows(t, db, "task_tags", "task_id = ?", task.ID); got != int64(len(task.Tags)) {
+					t.Fatalf("item %d has %d tags, want %d", i, got, len(task.Tags))
+				}
+			}
+
+			if got := countRows(t, db, "tasks", "creator_id = ?", creator_id) - tasks_before; got != created {
+				t.Fatalf("BatchCreate() wrote %d tasks, want %d", got, created)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Author of comment or owner of its task
+func commentOwnerCondition(user_id int32) (string, []any) {
+	condition, args := permissionCondition(user_id, domain.PermissionOwner)
+	return "(author_id = ? OR EXISTS (SELECT 1 FROM tasks WHERE tasks.id = comments.task_id AND " + condition + "))", append([]any{user_id}, args...)
+}
+
+type commentRepository struct {
+	Conn dbservice.Database
+}
+
+func NewCommentRepository(conn dbservice.Database) repository.CommentRepository {
+	return &commentRepository{
+		Conn: conn,
+	}
+}
+
+func (c *commentRepository) FetchByTask(ctx context.Context, task_id int32, offset int32, number int32) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	tx := c.Conn.Db.Where("task_id = ?", task_id).Order("created_at asc").Order("id asc")
+	if number > 0 {
+		tx = tx.Limit(int(number))
+	}
+
+	if err := tx.Offset(int(offset)).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (c *commentRepository) Create(ctx context.Context, info *domain.Comment) (*domain.Comment, error) {
+	if err := c.Conn.Db.Omit("Task", "Author").Create(info).Error; err != nil {
+		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+			if pgError.Code == "23503" {
+				return nil, domain.ErrTaskNotExists
+			}
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (c *commentRepository) Update(ctx context.Context, id int32, user_id int32, body string) (*domain.Comment, error) {
+	var comment domain.Comment
+	condition, args := commentOwnerCondition(user_id)
+	result := c.Conn.Db.Model(&comment).Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Where(condition, args...).
+		Updates(map[string]any{
+			"body":      body,
+			"edited_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, c.checkComment(id)
+	}
+
+	return &comment, nil
+}
+
+// Comment is soft deleted, it is hidden from every read
+func (c *commentRepository) Delete(ctx context.Context, id int32, user_id int32) error {
+	condition, args := commentOwnerCondition(user_id)
+	result := c.Conn.Db.Where(condition, args...).Delete(&domain.Comment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return c.checkComment(id)
+	}
+
+	return nil
+}
+
+// Find why a statement with owner condition did not touch the comment
+func (c *commentRepository) checkComment(id int32) error {
+	if err := c.Conn.Db.Select("id").First(&domain.Comment{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrCommentNotExists
+		}
+		return err
+	}
+
+	return domain.ErrPermissionDenied
+}
@@ -0,0 +1,131 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+// Condition of tasks which still have a blocker that is not closed
+const openBlockersCondition = `EXISTS (SELECT 1 FROM dependencies JOIN tasks AS blockers ON blockers.id = dependencies.blocker_id
+	WHERE dependencies.task_id = tasks.id AND blockers.status NOT IN ? AND blockers.deleted_at IS NULL)`
+
+// Get blocker and every task which blocks it, directly or through other tasks
+func getBlockerIds(tx *gorm.DB, blocker_id int32) ([]int32, error) {
+	var ids []int32
+	err := tx.Raw(`WITH RECURSIVE blockers AS (
+			SELECT ?::integer AS id
+			UNION
+			SELECT dependencies.blocker_id FROM dependencies JOIN blockers ON dependencies.task_id = blockers.id
+		)
+		SELECT id FROM blockers`, blocker_id).Scan(&ids).Error
+
+	return ids, err
+}
+
+// Lock task and every task its new blocker is blocked by. Two inserts which build a cycle together both reach
+// the task of the other one, so they wait for each other. Blockers are found again until all of them are locked
+func lockBlockers(tx *gorm.DB, task_id int32, blocker_id int32) ([]int32, error) {
+	locked := map[int32]bool{}
+	for {
+		blocker_ids, err := getBlockerIds(tx, blocker_id)
+		if err != nil {
+			return nil, err
+		}
+
+		added, err := lockIds(tx, dependencyLock, locked, append(blocker_ids, task_id))
+		if err != nil || !added {
+			return blocker_ids, err
+		}
+	}
+}
+
+type dependencyRepository struct {
+	Conn dbservice.Database
+}
+
+func NewDependencyRepository(conn dbservice.Database) repository.DependencyRepository {
+	return &dependencyRepository{
+		Conn: conn,
+	}
+}
+
+func (d *dependencyRepository) FetchByTask(ctx context.Context, task_id int32, user_id int32) ([]domain.Task, []domain.Task, error) {
+	if err := requirePermission(d.Conn.Db, task_id, user_id, domain.PermissionViewer); err != nil {
+		return nil, nil, err
+	}
+
+	var blockers []domain.Task
+	err := d.Conn.Db.Where("id IN (SELECT blocker_id FROM dependencies WHERE task_id = ?)", task_id).Order("id asc").Find(&blockers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var blocking []domain.Task
+	err = d.Conn.Db.Where("id IN (SELECT task_id FROM dependencies WHERE blocker_id = ?)", task_id).Order("id asc").Find(&blocking).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blockers, blocking, nil
+}
+
+func (d *dependencyRepository) Create(ctx context.Context, user_id int32, info *domain.Dependency) (*domain.Dependency, error) {
+	err := d.Conn.Db.Transaction(func(tx *gorm.DB) error {
+		if err := requirePermission(tx, info.TaskId, user_id, domain.PermissionEditor); err != nil {
+			return err
+		}
+		if err := requirePermission(tx, info.BlockerId, user_id, domain.PermissionViewer); err != nil {
+			return err
+		}
+
+		// Task must not be a blocker of its blocker, directly or through other tasks
+		blocker_ids, err := lockBlockers(tx, info.TaskId, info.BlockerId)
+		if err != nil {
+			return err
+		}
+		for _, blocker_id := range blocker_ids {
+			if blocker_id == info.TaskId {
+				return domain.ErrDependencyCycle
+			}
+		}
+
+		if err := tx.Omit("Task", "Blocker").Create(info).Error; err != nil {
+			if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+				if pgError.Code == "23505" {
+					return domain.ErrDependencyExists
+				}
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (d *dependencyRepository) Delete(ctx context.Context, task_id int32, blocker_id int32, user_id int32) error {
+	if err := requirePermission(d.Conn.Db, task_id, user_id, domain.PermissionEditor); err != nil {
+		return err
+	}
+
+	result := d.Conn.Db.Where("task_id = ? AND blocker_id = ?", task_id, blocker_id).Delete(&domain.Dependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrDependencyNotExists
+	}
+
+	return nil
+}
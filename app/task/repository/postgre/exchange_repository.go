@@ -0,0 +1,261 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tagDomain "todo-go-grpc/app/tag/domain"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Number of tasks read at once while they are exported
+const exportBatchSize = 500
+
+// Export only reads, every task gets its external id when it is created
+func (t *taskRepository) Export(ctx context.Context, user_id int32, write func(record *domain.TaskRecord) error) error {
+	var after_id int32
+	for {
+		var tasks []domain.Task
+		if err := t.Conn.Db.Preload("Tags").Where("creator_id = ? AND id > ?", user_id, after_id).
+			Order("id asc").Limit(exportBatchSize).Find(&tasks).Error; err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		// Parent may be created by another user, it is referred to by its external id all the same
+		parent_ids := []int32{}
+		for _, task := range tasks {
+			if task.ParentId != nil {
+				parent_ids = append(parent_ids, *task.ParentId)
+			}
+		}
+		parents := []domain.Task{}
+		if len(parent_ids) != 0 {
+			if err := t.Conn.Db.Select("id", "external_id").Where("id IN ?", parent_ids).Find(&parents).Error; err != nil {
+				return err
+			}
+		}
+		parent_external_ids := map[int32]string{}
+		for _, parent := range parents {
+			if parent.ExternalId != nil {
+				parent_external_ids[parent.ID] = *parent.ExternalId
+			}
+		}
+
+		for i := range tasks {
+			parent_external_id := ""
+			if tasks[i].ParentId != nil {
+				parent_external_id = parent_external_ids[*tasks[i].ParentId]
+			}
+			record := domain.NewTaskRecord(&tasks[i], parent_external_id)
+			if err := write(&record); err != nil {
+				return err
+			}
+			after_id = tasks[i].ID
+		}
+	}
+}
+
+// Every record is created in its own savepoint after its parent, so a failed record leaves the rest of import alone
+func (t *taskRepository) Import(ctx context.Context, user_id int32, records []domain.TaskRecord, options domain.ImportOptions) ([]domain.ImportResult, error) {
+	results := make([]domain.ImportResult, len(records))
+	for i, record := range records {
+		results[i] = domain.ImportResult{ExternalId: record.ExternalId}
+	}
+	fail := func(i int, err error) {
+		results[i].Status = domain.ImportFailed
+		results[i].Err = err
+	}
+
+	err := t.transaction(func(tx *gorm.DB) error {
+		// Tasks which already have external ids of records, trashed ones included
+		external_ids := []string{}
+		for _, record := range records {
+			if record.ExternalId != "" {
+				external_ids = append(external_ids, record.ExternalId)
+			}
+			if record.ParentExternalId != "" {
+				external_ids = append(external_ids, record.ParentExternalId)
+			}
+		}
+		task_ids := map[string]int32{}
+		if len(external_ids) != 0 {
+			var existing []domain.Task
+			if err := tx.Unscoped().Select("id", "external_id").Where("creator_id = ? AND external_id IN ?", user_id, external_ids).Find(&existing).Error; err != nil {
+				return err
+			}
+			for _, task := range existing {
+				task_ids[*task.ExternalId] = task.ID
+			}
+		}
+
+		tags, err := t.importTags(tx, records, options.CreateMissingTags)
+		if err != nil {
+			return err
+		}
+
+		// Records waiting to be created along with what they are created from
+		infos := map[int]*domain.Task{}
+		pending := []int{}
+		seen := map[string]bool{}
+		for i := range records {
+			record := &records[i]
+			if record.ExternalId != "" {
+				if id, ok := task_ids[record.ExternalId]; ok || seen[record.ExternalId] {
+					results[i].Status = domain.ImportSkipped
+					results[i].TaskId = id
+					continue
+				}
+				seen[record.ExternalId] = true
+			}
+
+			info, err := record.Task()
+			if err != nil {
+				fail(i, err)
+				continue
+			}
+			for _, value := range record.Tags {
+				tag, ok := tags[value]
+				if !ok {
+					err = fmt.Errorf("%w: %q", domain.ErrTagNotExists, value)
+					break
+				}
+				info.Tags = append(info.Tags, tag)
+			}
+			if err != nil {
+				fail(i, err)
+				continue
+			}
+
+			prepareCreate(user_id, info)
+			infos[i] = info
+			pending = append(pending, i)
+		}
+
+		// Parents are created first, record is left for the next round while its parent is still pending
+		for len(pending) != 0 {
+			waiting := map[string]bool{}
+			for _, i := range pending {
+				waiting[records[i].ExternalId] = true
+			}
+
+			next := []int{}
+			for _, i := range pending {
+				record, info := &records[i], infos[i]
+				if record.ParentExternalId != "" {
+					parent_id, ok := task_ids[record.ParentExternalId]
+					if !ok && waiting[record.ParentExternalId] {
+						next = append(next, i)
+						continue
+					}
+					if !ok {
+						fail(i, fmt.Errorf("%w: %q", domain.ErrParentNotExists, record.ParentExternalId))
+						continue
+					}
+					info.ParentId = &parent_id
+				}
+
+				if err := tx.Transaction(func(tx *gorm.DB) error {
+					return t.create(tx, user_id, info)
+				}); err != nil {
+					fail(i, err)
+					continue
+				}
+				results[i].Status = domain.ImportCreated
+				results[i].TaskId = info.ID
+				if record.ExternalId != "" {
+					task_ids[record.ExternalId] = info.ID
+				}
+			}
+
+			// Records which only wait for each other refer to their parents in a cycle
+			if len(next) == len(pending) {
+				for _, i := range next {
+					fail(i, fmt.Errorf("%w: parent %q", domain.ErrTaskCycle, records[i].ParentExternalId))
+				}
+				break
+			}
+			pending = next
+		}
+
+		// Record repeated in the same import refers to the task created by its first occurrence
+		for i := range results {
+			if results[i].Status == domain.ImportSkipped && results[i].TaskId == 0 {
+				results[i].TaskId = task_ids[results[i].ExternalId]
+			}
+		}
+
+		if options.DryRun {
+			return domain.ErrImportDryRun
+		}
+		return nil
+	})
+
+	if errors.Is(err, domain.ErrImportDryRun) {
+		// Nothing is written, so there is no task to refer to
+		for i := range results {
+			results[i].TaskId = 0
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Find tags of records by value, missing tags are created when create_missing is set
+func (t *taskRepository) importTags(tx *gorm.DB, records []domain.TaskRecord, create_missing bool) (map[string]tagDomain.Tag, error) {
+	values := []string{}
+	for _, record := range records {
+		values = append(values, record.Tags...)
+	}
+	tags := map[string]tagDomain.Tag{}
+	if len(values) == 0 {
+		return tags, nil
+	}
+
+	var existing []tagDomain.Tag
+	if err := tx.Where("value IN ?", values).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range existing {
+		tags[tag.Value] = tag
+	}
+	if !create_missing {
+		return tags, nil
+	}
+
+	missing := []tagDomain.Tag{}
+	missing_values := []string{}
+	for _, value := range values {
+		if _, ok := tags[value]; !ok {
+			tags[value] = tagDomain.Tag{}
+			missing = append(missing, tagDomain.Tag{Value: value})
+			missing_values = append(missing_values, value)
+		}
+	}
+	if len(missing) == 0 {
+		return tags, nil
+	}
+
+	// Tag created meanwhile by another import is taken as it is
+	if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "value"}}, DoNothing: true}).Create(&missing).Error; err != nil {
+		return nil, err
+	}
+	var created []tagDomain.Tag
+	if err := tx.Where("value IN ?", missing_values).Find(&created).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range created {
+		tags[tag.Value] = tag
+	}
+
+	return tags, nil
+}
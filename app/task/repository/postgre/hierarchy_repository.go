@@ -0,0 +1,259 @@
+package postgre
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Get ids from task up to its top-level ancestor, task itself is first
+func getAncestorIds(tx *gorm.DB, id int32) ([]int32, error) {
+	var ids []int32
+	err := tx.Raw(`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS level FROM tasks WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT tasks.id, tasks.parent_id, ancestors.level + 1 FROM tasks JOIN ancestors ON tasks.id = ancestors.parent_id
+		)
+		SELECT id FROM ancestors ORDER BY level`, id).Scan(&ids).Error
+
+	return ids, err
+}
+
+// Get number of levels of the subtree rooted at task, trashed descendants are not counted
+func getSubtreeHeight(tx *gorm.DB, id int32) (int32, error) {
+	var height int32
+	err := tx.Raw(`WITH RECURSIVE descendants AS (
+			SELECT id, 1 AS level FROM tasks WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT tasks.id, descendants.level + 1 FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+			WHERE tasks.deleted_at IS NULL
+		)
+		SELECT COALESCE(MAX(level), 0) FROM descendants`, id).Scan(&height).Error
+
+	return height, err
+}
+
+// Lock trees which tasks are in, so two moves can not build a cycle or go too deep together.
+// Top-level task of a tree only changes when it is moved, which needs lock of its tree,
+// so roots are found again until every one of them is locked
+func lockTrees(tx *gorm.DB, ids ...int32) error {
+	locked := map[int32]bool{}
+	for {
+		roots := []int32{}
+		for _, id := range ids {
+			ancestor_ids, err := getAncestorIds(tx, id)
+			if err != nil {
+				return err
+			}
+			if len(ancestor_ids) != 0 {
+				roots = append(roots, ancestor_ids[len(ancestor_ids)-1])
+			}
+		}
+
+		added, err := lockIds(tx, hierarchyLock, locked, roots)
+		if err != nil || !added {
+			return err
+		}
+	}
+}
+
+// Check tasks of given height can be placed under parent. Zero id is a new task,
+// otherwise task is moved along with its subtree and height is the height of that subtree
+func (t *taskRepository) checkParent(tx *gorm.DB, id int32, parent_id int32, height int32) error {
+	ids := []int32{parent_id}
+	if id != 0 {
+		ids = append(ids, id)
+	}
+	if err := lockTrees(tx, ids...); err != nil {
+		return err
+	}
+
+	if id != 0 {
+		var err error
+		if height, err = getSubtreeHeight(tx, id); err != nil {
+			return err
+		}
+		if height == 0 {
+			return domain.ErrTaskNotExists
+		}
+	}
+
+	ancestor_ids, err := getAncestorIds(tx, parent_id)
+	if err != nil {
+		return err
+	}
+	if len(ancestor_ids) == 0 {
+		return domain.ErrParentNotExists
+	}
+
+	for _, ancestor_id := range ancestor_ids {
+		if ancestor_id == id {
+			return domain.ErrTaskCycle
+		}
+	}
+
+	if int32(len(ancestor_ids))+height > t.Config.MaxDepth {
+		return domain.ErrTaskTooDeep
+	}
+
+	return nil
+}
+
+// Descendant is only done along with task when its blockers are closed or done along with it too
+func checkChildrenBlockers(tx *gorm.DB, descendants string, id int32) error {
+	var blocked []int32
+	if err := tx.Raw(descendants+`
+		SELECT tasks.id FROM tasks
+		JOIN dependencies ON dependencies.task_id = tasks.id
+		JOIN tasks AS blockers ON blockers.id = dependencies.blocker_id
+		WHERE tasks.id IN (SELECT id FROM descendants) AND tasks.status NOT IN ? AND tasks.deleted_at IS NULL
+			AND blockers.status NOT IN ? AND blockers.deleted_at IS NULL
+			AND blockers.id <> ? AND blockers.id NOT IN (SELECT id FROM descendants)
+		LIMIT 1`, id, domain.ClosedStatuses, domain.ClosedStatuses, id).Scan(&blocked).Error; err != nil {
+		return err
+	}
+	if len(blocked) != 0 {
+		return fmt.Errorf("%w: subtask %d", domain.ErrTaskBlocked, blocked[0])
+	}
+	return nil
+}
+
+// Apply complete parent rule before open task is marked as done, get previous state of children it completed.
+// Force skips blocker check of children as it does for the task
+func (t *taskRepository) completeChildren(tx *gorm.DB, id int32, user_id int32, now time.Time, force bool) ([]domain.Task, error) {
+	switch t.Config.CompleteParentRule {
+	case domain.CompleteParentCascade:
+		// Open descendants are done whatever their status is, each change is recorded first
+		descendants := `WITH RECURSIVE descendants AS (
+				SELECT id FROM tasks WHERE parent_id = ?
+				UNION ALL
+				SELECT tasks.id FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+			)`
+		var children []domain.Task
+		if err := tx.Raw(descendants+`
+			SELECT * FROM tasks
+			WHERE id IN (SELECT id FROM descendants) AND status NOT IN ? AND deleted_at IS NULL`, id, domain.ClosedStatuses).Scan(&children).Error; err != nil {
+			return nil, err
+		}
+		if len(children) == 0 {
+			return nil, nil
+		}
+		if !force {
+			if err := checkChildrenBlockers(tx, descendants, id); err != nil {
+				return nil, err
+			}
+		}
+
+		entries := []domain.HistoryEntry{}
+		for _, child := range children {
+			entries = append(entries, domain.HistoryEntry{
+				TaskId:  child.ID,
+				ActorId: user_id,
+				Action:  domain.HistoryUpdated,
+				Changes: domain.FieldChanges{"status": {Before: child.Status.String(), After: domain.StatusDone.String()}},
+			})
+		}
+		if err := tx.Omit("Task").Create(&entries).Error; err != nil {
+			return nil, err
+		}
+
+		if err := tx.Exec(descendants+`
+			INSERT INTO status_changes (task_id, from_status, to_status, changed_by, changed_at)
+			SELECT id, status, ?, ?, ? FROM tasks
+			WHERE id IN (SELECT id FROM descendants) AND status NOT IN ? AND deleted_at IS NULL`, id, domain.StatusDone, user_id, now, domain.ClosedStatuses).Error; err != nil {
+			return nil, err
+		}
+
+		err := tx.Exec(descendants+`
+			UPDATE tasks SET status = ?, is_done = true, done_at = ?, status_changed_at = ?, status_changed_by = ?, version = version + 1
+			WHERE id IN (SELECT id FROM descendants) AND status NOT IN ? AND deleted_at IS NULL`, id, domain.StatusDone, now, now, user_id, domain.ClosedStatuses).Error
+		if err != nil {
+			return nil, err
+		}
+
+		return children, nil
+	default:
+		var open_children int64
+		if err := tx.Model(&domain.Task{}).Where("parent_id = ? AND status NOT IN ?", id, domain.ClosedStatuses).Count(&open_children).Error; err != nil {
+			return nil, err
+		}
+		if open_children > 0 {
+			return nil, domain.ErrTaskHasOpenChildren
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *taskRepository) Move(ctx context.Context, id int32, user_id int32, parent_id *int32, before_id int32, after_id int32, version int32) (*domain.Task, error) {
+	var task domain.Task
+	err := t.transaction(func(tx *gorm.DB) error {
+		if err := requirePermission(tx, id, user_id, domain.PermissionEditor); err != nil {
+			return err
+		}
+
+		previous, err := lockTask(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if parent_id != nil {
+			if err := t.checkParent(tx, id, *parent_id, 0); err != nil {
+				return err
+			}
+			if err := requirePermission(tx, *parent_id, user_id, domain.PermissionEditor); err != nil {
+				return err
+			}
+		}
+
+		// Task keeps its rank unless it is placed by hand or goes to another list
+		new_task_map := map[string]any{
+			"parent_id": parent_id,
+			"version":   gorm.Expr("version + 1"),
+		}
+		parent_changed := (parent_id == nil) != (previous.ParentId == nil) || (parent_id != nil && *parent_id != *previous.ParentId)
+		if before_id != 0 || after_id != 0 || parent_changed {
+			rank, err := placeTask(tx, rankList(tx, parent_id, previous.ProjectId, previous.CreatorId), id, before_id, after_id)
+			if err != nil {
+				return err
+			}
+			new_task_map["rank"] = rank
+		}
+
+		// Check version and move in one statement
+		query := tx.Model(&task).Clauses(clause.Returning{}).Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+
+		result := query.Updates(new_task_map)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return checkVersion(tx, id)
+		}
+
+		changes := domain.DiffTasks(previous, &task)
+		if task.Rank != previous.Rank {
+			changes["rank"] = domain.FieldChange{Before: previous.Rank, After: task.Rank}
+		}
+		if err := recordHistory(tx, id, user_id, domain.HistoryMoved, changes); err != nil {
+			return err
+		}
+
+		// Undo puts task back under its previous parent at its previous rank
+		undo_tasks := domain.UndoTasks{{TaskId: id, Version: task.Version, Previous: domain.NewTaskState(previous)}}
+		return recordUndo(tx, &domain.UndoOperation{UserId: user_id, Kind: domain.UndoUpdate, Tasks: undo_tasks})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
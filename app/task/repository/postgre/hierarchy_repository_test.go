@@ -0,0 +1,67 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+func TestCompleteParentCascade(t *testing.T) {
+	repo, db := newTestRepository(t, repository.Config{CompleteParentRule: domain.CompleteParentCascade})
+	ctx := context.Background()
+	owner_id := createTestUser(t, db, "owner")
+
+	series := domain.Series{Rule: "FREQ=DAILY"}
+	if err := db.Create(&series).Error; err != nil {
+		t.Fatalf("create series error: %v", err)
+	}
+	due := time.Now().Add(time.Hour)
+	parent := createTestTask(t, db, domain.Task{Name: "parent", CreatorId: owner_id})
+	child := createTestTask(t, db, domain.Task{Name: "child", CreatorId: owner_id, ParentId: &parent.ID, SeriesId: &series.ID, DueAt: &due})
+	grandchild := createTestTask(t, db, domain.Task{Name: "grandchild", CreatorId: owner_id, ParentId: &child.ID, Status: domain.StatusInProgress})
+
+	statusOf := func(id int32) domain.Status {
+		var task domain.Task
+		if err := db.Unscoped().First(&task, id).Error; err != nil {
+			t.Fatalf("find task %d error: %v", id, err)
+		}
+		return task.Status
+	}
+	openOccurrences := func() int64 {
+		return countRows(t, db, "tasks", "series_id = ? AND status NOT IN ? AND deleted_at IS NULL", series.ID, domain.ClosedStatuses)
+	}
+
+	done := *parent
+	done.Status = domain.StatusDone
+	if _, err := repo.Update(ctx, parent.ID, owner_id, &done, nil, nil, false); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	for _, id := range []int32{parent.ID, child.ID, grandchild.ID} {
+		if got := statusOf(id); got != domain.StatusDone {
+			t.Fatalf("status of task %d after Update() = %v, want %v", id, got, domain.StatusDone)
+		}
+	}
+	// Recurring child done by its parent brings its next occurrence
+	if got := openOccurrences(); got != 1 {
+		t.Fatalf("%d open occurrences after Update(), want 1", got)
+	}
+
+	if _, err := repo.Undo(ctx, owner_id); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	want := map[int32]domain.Status{parent.ID: domain.StatusTodo, child.ID: domain.StatusTodo, grandchild.ID: domain.StatusInProgress}
+	for id, status := range want {
+		if got := statusOf(id); got != status {
+			t.Fatalf("status of task %d after Undo() = %v, want %v", id, got, status)
+		}
+	}
+	// Next occurrence goes away with the undo, only the child is left open
+	if got := openOccurrences(); got != 1 {
+		t.Fatalf("%d open occurrences after Undo(), want 1", got)
+	}
+	if got := countRows(t, db, "tasks", "series_id = ?", series.ID); got != 1 {
+		t.Fatalf("%d occurrences after Undo(), want 1", got)
+	}
+}
@@ -0,0 +1,103 @@
+package postgre
+
+import (
+	"context"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"gorm.io/gorm"
+)
+
+type historyRepository struct {
+	Conn dbservice.Database
+}
+
+func NewHistoryRepository(conn dbservice.Database) repository.HistoryRepository {
+	return &historyRepository{
+		Conn: conn,
+	}
+}
+
+// Latest entries come first
+func (h *historyRepository) FetchByTask(ctx context.Context, task_id int32, user_id int32, offset int32, number int32) ([]domain.HistoryEntry, error) {
+	if err := requirePermission(h.Conn.Db, task_id, user_id, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	var entries []domain.HistoryEntry
+	tx := h.Conn.Db.Where("task_id = ?", task_id).Order("created_at desc").Order("id desc")
+	if err := tx.Limit(int(number)).Offset(int(offset)).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Every transaction with id below xmin of snapshot has ended, so no entry can appear behind the cursor later
+const finishedTxCondition = "history_entries.tx_id < txid_snapshot_xmin(txid_current_snapshot())"
+
+// Task of entry is loaded unless it is in trash, permission is checked on its current place in hierarchy.
+// Purged task is gone along with its permissions, so its tombstone is only sent to user who purged it
+func (h *historyRepository) FetchEvents(ctx context.Context, user_id int32, after domain.HistoryCursor, limit int) ([]domain.HistoryEntry, bool, error) {
+	condition, args := permissionCondition(user_id, domain.PermissionViewer)
+	after_cursor := "(history_entries.tx_id, history_entries.id) > (?, ?)"
+
+	var entries []domain.HistoryEntry
+	err := h.Conn.Db.Preload("Task").Joins("LEFT JOIN tasks ON tasks.id = history_entries.task_id").
+		Where(after_cursor, after.TxId, after.Id).Where(finishedTxCondition).
+		Where(h.Conn.Db.Where("tasks.id IS NULL AND history_entries.actor_id = ?", user_id).Or(condition, args...)).
+		Order("history_entries.tx_id asc").Order("history_entries.id asc").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	var waiting bool
+	err = h.Conn.Db.Raw("SELECT EXISTS (SELECT 1 FROM history_entries WHERE "+after_cursor+" AND NOT "+finishedTxCondition+")", after.TxId, after.Id).Scan(&waiting).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	return entries, waiting, nil
+}
+
+func (h *historyRepository) LastEventCursor(ctx context.Context) (domain.HistoryCursor, error) {
+	var xmin int64
+	if err := h.Conn.Db.Raw("SELECT txid_snapshot_xmin(txid_current_snapshot())").Scan(&xmin).Error; err != nil {
+		return domain.HistoryCursor{}, err
+	}
+	// Entries of transaction xmin itself have ids above zero, so they are still sent
+	return domain.HistoryCursor{TxId: xmin}, nil
+}
+
+// Write history entry of task, update which changes no field is not written
+func recordHistory(tx *gorm.DB, task_id int32, actor_id int32, action domain.HistoryAction, changes domain.FieldChanges) error {
+	if len(changes) == 0 && (action == domain.HistoryUpdated || action == domain.HistoryMoved) {
+		return nil
+	}
+
+	return tx.Omit("Task").Create(&domain.HistoryEntry{
+		TaskId:  task_id,
+		ActorId: actor_id,
+		Action:  action,
+		Changes: changes,
+	}).Error
+}
+
+// Write the same entry without changed fields for several tasks in one statement
+func recordHistoryOfTasks(tx *gorm.DB, task_ids []int32, actor_id int32, action domain.HistoryAction) error {
+	if len(task_ids) == 0 {
+		return nil
+	}
+
+	entries := []domain.HistoryEntry{}
+	for _, task_id := range task_ids {
+		entries = append(entries, domain.HistoryEntry{
+			TaskId:  task_id,
+			ActorId: actor_id,
+			Action:  action,
+			Changes: domain.FieldChanges{},
+		})
+	}
+	return tx.Omit("Task").Create(&entries).Error
+}
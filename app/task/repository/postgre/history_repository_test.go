@@ -0,0 +1,102 @@
+package postgre
+
+import (
+	"context"
+	"testing"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestFetchEvents(t *testing.T) {
+	_, db := newTestRepository(t, repository.Config{})
+	repo := &historyRepository{Conn: dbservice.Database{Db: db}}
+	ctx := context.Background()
+	user_id := createTestUser(t, db, "watcher")
+	stranger_id := createTestUser(t, db, "stranger")
+	task := createTestTask(t, db, domain.Task{Name: "task", CreatorId: user_id})
+	other_task := createTestTask(t, db, domain.Task{Name: "other", CreatorId: stranger_id})
+
+	record := func(t *testing.T, tx *gorm.DB, task_id int32, name string) {
+		changes := domain.FieldChanges{"name": {Before: "", After: name}}
+		if err := recordHistory(tx, task_id, user_id, domain.HistoryUpdated, changes); err != nil {
+			t.Fatalf("recordHistory() error: %v", err)
+		}
+	}
+	fetch := func(t *testing.T, after domain.HistoryCursor, limit int) ([]string, domain.HistoryCursor, bool) {
+		entries, waiting, err := repo.FetchEvents(ctx, user_id, after, limit)
+		if err != nil {
+			t.Fatalf("FetchEvents() error: %v", err)
+		}
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Changes["name"].After.(string))
+			after = domain.HistoryCursor{TxId: entry.TxId, Id: entry.ID}
+		}
+		return names, after, waiting
+	}
+	assertNames := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("FetchEvents() = %v, want %v", got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("FetchEvents() = %v, want %v", got, want)
+			}
+		}
+	}
+
+	start, err := repo.LastEventCursor(ctx)
+	if err != nil {
+		t.Fatalf("LastEventCursor() error: %v", err)
+	}
+
+	t.Run("resume from cursor", func(t *testing.T) {
+		for _, name := range []string{"a", "b", "c"} {
+			record(t, db, task.ID, name)
+		}
+		// Entries of tasks user can not see are skipped
+		record(t, db, other_task.ID, "hidden")
+
+		names, cursor, _ := fetch(t, start, 2)
+		assertNames(t, names, "a", "b")
+		names, cursor, _ = fetch(t, cursor, 2)
+		assertNames(t, names, "c")
+		names, _, _ = fetch(t, cursor, 2)
+		assertNames(t, names)
+		start = cursor
+	})
+
+	t.Run("late commit", func(t *testing.T) {
+		// Transaction which takes its id first commits last, its entry gets the higher id
+		late := db.Begin()
+		defer late.Rollback()
+		if err := late.Exec("SELECT txid_current()").Error; err != nil {
+			t.Fatalf("txid_current() error: %v", err)
+		}
+		record(t, db, task.ID, "early")
+
+		names, cursor, waiting := fetch(t, start, 10)
+		assertNames(t, names)
+		if !waiting {
+			t.Fatalf("FetchEvents() waiting = false while earlier transaction is open, want true")
+		}
+
+		record(t, late, task.ID, "late")
+		if err := late.Commit().Error; err != nil {
+			t.Fatalf("commit error: %v", err)
+		}
+
+		// Entries come in order of transactions, not of ids
+		names, cursor, waiting = fetch(t, cursor, 10)
+		assertNames(t, names, "late", "early")
+		if waiting {
+			t.Fatalf("FetchEvents() waiting = true after every transaction ended, want false")
+		}
+		names, _, _ = fetch(t, cursor, 10)
+		assertNames(t, names)
+	})
+}
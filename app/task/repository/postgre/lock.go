@@ -0,0 +1,46 @@
+package postgre
+
+import (
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Advisory locks are held until the end of transaction. Each lock has a name of what kind of rows it protects
+// and a key of those rows, so only writers of the same rows wait for each other
+type lockName string
+
+const (
+	// Ranks of one list of tasks, keyed by list
+	rankLock lockName = "task_rank"
+	// Hierarchy of one tree of tasks, keyed by its top-level task
+	hierarchyLock lockName = "task_hierarchy"
+	// Dependencies of one task, keyed by task
+	dependencyLock lockName = "task_dependency"
+	// Attachment quota of one user, keyed by uploader
+	attachmentLock lockName = "attachment_quota"
+)
+
+func lock(tx *gorm.DB, name lockName, key string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?), hashtext(?))", string(name), key).Error
+}
+
+// Lock ids which are not locked yet in ascending order, so transactions locking the same ids do not deadlock
+func lockIds(tx *gorm.DB, name lockName, locked map[int32]bool, ids []int32) (bool, error) {
+	ids = append([]int32{}, ids...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	added := false
+	for _, id := range ids {
+		if locked[id] {
+			continue
+		}
+		if err := lock(tx, name, strconv.Itoa(int(id))); err != nil {
+			return false, err
+		}
+		locked[id] = true
+		added = true
+	}
+	return added, nil
+}
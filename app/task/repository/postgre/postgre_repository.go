@@ -3,6 +3,8 @@ package postgre
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 	"todo-go-grpc/app/dbservice"
 	tagDomain "todo-go-grpc/app/tag/domain"
 	"todo-go-grpc/app/task/domain"
@@ -10,18 +12,44 @@ import (
 
 	"github.com/jackc/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type taskRepository struct {
-	Conn dbservice.Database
+	Conn   dbservice.Database
+	Config repository.Config
 }
 
-func NewTaskRepository(conn dbservice.Database) repository.TaskRepository {
+func NewTaskRepository(conn dbservice.Database, config repository.Config) repository.TaskRepository {
 	return &taskRepository{
-		Conn: conn,
+		Conn:   conn,
+		Config: config,
 	}
 }
 
+// Run fn in transaction, watchers are woken up once changes of tasks are committed
+func (t *taskRepository) transaction(fn func(tx *gorm.DB) error) error {
+	if err := t.Conn.Db.Transaction(fn); err != nil {
+		return err
+	}
+	if t.Config.Broker != nil {
+		t.Config.Broker.Publish()
+	}
+	return nil
+}
+
+// Progress of children, number of comments, tracked time and permission of user along with task
+const countsSelect = `tasks.*,
+		(SELECT count(*) FROM tasks AS children WHERE children.parent_id = tasks.id AND children.is_done AND children.deleted_at IS NULL) AS children_done,
+		(SELECT count(*) FROM tasks AS children WHERE children.parent_id = tasks.id AND children.deleted_at IS NULL) AS children_total,
+		(SELECT count(*) FROM comments WHERE comments.task_id = tasks.id AND comments.deleted_at IS NULL) AS comment_count,
+		(SELECT ` + trackedSecondsExpr + ` FROM time_entries WHERE time_entries.task_id = tasks.id) AS tracked_seconds,
+		COALESCE(access.permission, 0) AS permission`
+
+func withCounts(tx *gorm.DB, user_id int32) *gorm.DB {
+	return withAccess(tx, user_id).Select(countsSelect)
+}
+
 func SearchUserByIds(ctx context.Context, ids []int32, db *gorm.DB) (tasks []domain.Task, err error) {
 	if err = db.Where("id IN ?", ids).Find(&tasks).Error; err != nil {
 		return nil, err
@@ -32,23 +60,82 @@ func SearchUserByIds(ctx context.Context, ids []int32, db *gorm.DB) (tasks []dom
 func (t *taskRepository) Fetch(ctx context.Context, user_id int32, offset int32, number int32, conditions map[string]any) ([]domain.Task, error) {
 	var tasks []domain.Task
 	var queryString string
-	tx := t.Conn.Db.Preload("UserCreator").Preload("Tags")
+	tx := t.Conn.Db.Preload("UserCreator").Preload("Tags").Preload("Series")
 	queryArgs := []interface{}{}
+	addCondition := func(condition string, args ...any) {
+		if queryString != "" {
+			queryString += " AND "
+		}
+		queryString += condition
+		queryArgs = append(queryArgs, args...)
+	}
+
+	// Matching tasks are selected along with their relevance and highlighted name and description
+	search_query, searching := conditions["query"]
+	if searching {
+		vector := searchVector(t.Config.SearchLanguage)
+		tsquery := fmt.Sprintf("websearch_to_tsquery('%s', ?)", t.Config.SearchLanguage)
+		headline := fmt.Sprintf("ts_headline('%s', %%s, %s, '%s')", t.Config.SearchLanguage, tsquery, searchHeadlineOptions)
+		tx = withAccess(tx, user_id).Select(countsSelect+`,
+			ts_rank(`+vector+`, `+tsquery+`) AS search_rank,
+			`+fmt.Sprintf(headline, "tasks.name")+` AS name_highlight,
+			`+fmt.Sprintf(headline, "tasks.description")+` AS description_highlight`,
+			search_query, search_query, search_query)
+		addCondition(vector+" @@ "+tsquery, search_query)
+	} else {
+		tx = withCounts(tx, user_id)
+	}
 
 	// Check condition and add to queryString
 	if value, ok := conditions["name"]; ok {
-		queryString += "name LIKE ?"
-		queryArgs = append(queryArgs, "%"+value.(string)+"%")
+		addCondition("name LIKE ?", "%"+value.(string)+"%")
 	}
 	// Cannot search by tag, association not supported
 	// if tags, ok := conditions["tags"]; ok && tags != nil {
-	// 	if queryString != "" {
-	// 		queryString += " AND "
-	// 	}
-
-	// 	queryString += "tag_id IN ?"
-	// 	queryArgs = append(queryArgs, tags.([]int32))
+	// 	addCondition("tag_id IN ?", tags.([]int32))
 	// }
+	if now, ok := conditions["overdue"]; ok {
+		// Date only task is overdue after its whole day passed
+		addCondition("status NOT IN ? AND ((due_all_day AND due_at + interval '1 day' <= ?) OR (NOT due_all_day AND due_at <= ?))", domain.ClosedStatuses, now, now)
+	}
+	if due_from, ok := conditions["due_from"]; ok {
+		addCondition("due_at >= ?", due_from)
+	}
+	if due_to, ok := conditions["due_to"]; ok {
+		addCondition("due_at < ?", due_to)
+	}
+	if statuses, ok := conditions["statuses"]; ok {
+		addCondition("status IN ?", statuses)
+	}
+	// Actionable task is open and has no open blocker
+	if _, ok := conditions["actionable"]; ok {
+		addCondition("status NOT IN ? AND NOT "+openBlockersCondition, domain.ClosedStatuses, domain.ClosedStatuses)
+	}
+	if priorities, ok := conditions["priorities"]; ok {
+		addCondition("priority IN ?", priorities)
+	}
+	// Only tasks which user can view are fetched
+	addCondition("access.permission >= ?", domain.PermissionViewer)
+	switch conditions["ownership"] {
+	case "CREATED_BY_ME":
+		addCondition("creator_id = ?", user_id)
+	case "ASSIGNED_TO_ME":
+		addCondition("assignee_id = ?", user_id)
+	case "SHARED_WITH_ME":
+		addCondition("creator_id <> ? AND (assignee_id IS NULL OR assignee_id <> ?)", user_id, user_id)
+	}
+	// Tasks of archived projects are hidden unless their project is given
+	if project_id, ok := conditions["project_id"]; ok {
+		addCondition("project_id = ?", project_id)
+	} else if _, ok := conditions["include_archived"]; !ok {
+		addCondition("NOT EXISTS (SELECT 1 FROM projects WHERE projects.id = tasks.project_id AND projects.archived)")
+	}
+	// Only top-level tasks are fetched when no parent is given
+	if parent_id, ok := conditions["parent_id"]; ok {
+		addCondition("parent_id = ?", parent_id)
+	} else if _, ok := conditions["all_levels"]; !ok {
+		addCondition("parent_id IS NULL")
+	}
 
 	if queryString != "" {
 		tx = tx.Where(queryString, queryArgs...)
@@ -61,7 +148,23 @@ func (t *taskRepository) Fetch(ctx context.Context, user_id int32, offset int32,
 			tx = tx.Order("created_at asc")
 		case "TIME_CREATE_DESC":
 			tx = tx.Order("created_at desc")
+		case "DUE_DATE_ASC":
+			tx = tx.Order("due_at asc nulls last").Order("id asc")
+		case "DUE_DATE_DESC":
+			tx = tx.Order("due_at desc nulls last").Order("id asc")
+		case "PRIORITY_DESC_TIME_CREATE_ASC":
+			tx = tx.Order("priority desc").Order("created_at asc")
+		case "PRIORITY_DESC_TIME_CREATE_DESC":
+			tx = tx.Order("priority desc").Order("created_at desc")
+		case "PRIORITY_ASC_TIME_CREATE_ASC":
+			tx = tx.Order("priority asc").Order("created_at asc")
+		case "PRIORITY_ASC_TIME_CREATE_DESC":
+			tx = tx.Order("priority asc").Order("created_at desc")
+		case "MANUAL":
+			tx = tx.Order(rankOrder + " asc").Order("id asc")
 		}
+	} else if searching {
+		tx = tx.Order("search_rank desc").Order("id asc")
 	} else {
 		tx = tx.Order("id asc")
 	}
@@ -70,55 +173,256 @@ func (t *taskRepository) Fetch(ctx context.Context, user_id int32, offset int32,
 		return nil, err
 	}
 
+	for i := range tasks {
+		tasks[i].Shared = isShared(&tasks[i], user_id)
+	}
+
 	return tasks, nil
 }
 
-func (t *taskRepository) GetByID(ctx context.Context, id int32) (*domain.Task, error) {
+func (t *taskRepository) GetByID(ctx context.Context, id int32, user_id int32) (*domain.Task, error) {
 	var task domain.Task
-	if err := t.Conn.Db.Preload("UserCreator").Preload("Tags").First(&task, id).Error; err != nil {
+	tx := t.Conn.Db.Preload("UserCreator").Preload("Tags").Preload("Series").Preload("Children", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("id asc")
+	})
+	if err := withCounts(tx, user_id).First(&task, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrTaskNotExists
 		}
 
 		return nil, err
 	}
+	if task.Permission < domain.PermissionViewer {
+		return nil, domain.ErrPermissionDenied
+	}
 
+	task.Shared = isShared(&task, user_id)
 	return &task, nil
 }
 
-func (t *taskRepository) Create(ctx context.Context, creator_id int32, info *domain.Task) (*domain.Task, error) {
+func prepareCreate(creator_id int32, info *domain.Task) {
 	info.CreatorId = creator_id
-	if err := t.Conn.Db.Create(&info).Error; err != nil {
+	// Status wins over IsDone, client which does not know status only sends IsDone
+	if info.Status == domain.StatusUnspecified {
+		info.Status = info.Status.FromIsDone(info.IsDone)
+	}
+	info.IsDone = info.Status.IsDone()
+	// Imported task keeps the time it was done at
+	if info.IsDone && info.DoneAt.IsZero() {
+		info.DoneAt = time.Now()
+	}
+}
+
+// Check user can create task under its parent and in its project
+func (t *taskRepository) checkCreate(tx *gorm.DB, creator_id int32, info *domain.Task) error {
+	if info.ParentId != nil {
+		if err := t.checkParent(tx, 0, *info.ParentId, 1); err != nil {
+			return err
+		}
+		if err := requirePermission(tx, *info.ParentId, creator_id, domain.PermissionEditor); err != nil {
+			return err
+		}
+	}
+	// Only owner of project can add tasks to it
+	if info.ProjectId != nil {
+		if err := requireProject(tx, *info.ProjectId, creator_id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *taskRepository) Create(ctx context.Context, creator_id int32, info *domain.Task) (*domain.Task, error) {
+	prepareCreate(creator_id, info)
+	err := t.transaction(func(tx *gorm.DB) error {
+		return t.create(tx, creator_id, info)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Create task at the end of its list in transaction, info must be prepared by prepareCreate
+func (t *taskRepository) create(tx *gorm.DB, creator_id int32, info *domain.Task) error {
+	if err := t.checkCreate(tx, creator_id, info); err != nil {
+		return err
+	}
+
+	rank, err := placeTask(tx, rankList(tx, info.ParentId, info.ProjectId, creator_id), 0, 0, 0)
+	if err != nil {
+		return err
+	}
+	info.Rank = rank
+
+	if err := tx.Create(&info).Error; err != nil {
 		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
 			if pgError.Code == "23503" {
-				return nil, domain.ErrTagNotExists
+				return domain.ErrTagNotExists
+			}
+			// External id is already used by another task of creator
+			if pgError.Code == "23505" {
+				return domain.ErrTaskExists
 			}
 		}
-		return nil, err
+		return err
 	}
 
 	// Add tags to task
-	if err := t.Conn.Db.Model(&info).Association("Tags").Append(&info.Tags); err != nil {
-		return nil, err
+	if err := tx.Model(&info).Association("Tags").Append(&info.Tags); err != nil {
+		return err
+	}
+
+	if err := recordHistory(tx, info.ID, creator_id, domain.HistoryCreated, domain.DiffTasks(nil, info)); err != nil {
+		return err
 	}
 
 	// if err := tx.Model(&new_task).Association("UserCreator").Replace(&new_task.UserCreator); err != nil {
 	// 	return domain.Task{}, err
 	// }
 
-	return info, nil
+	return nil
 }
 
-func (t *taskRepository) Update(ctx context.Context, id int32, new_info *domain.Task, tags_add []int32, tags_remove []int32) (*domain.Task, error) {
+func (t *taskRepository) Update(ctx context.Context, id int32, user_id int32, new_info *domain.Task, tags_add []int32, tags_remove []int32, force bool) (*domain.Task, error) {
+	var task *domain.Task
+	err := t.transaction(func(tx *gorm.DB) error {
+		var undo_tasks domain.UndoTasks
+		var err error
+		if task, undo_tasks, err = t.update(tx, id, user_id, new_info, tags_add, tags_remove, force); err != nil {
+			return err
+		}
+
+		return recordUndo(tx, &domain.UndoOperation{UserId: user_id, Kind: domain.UndoUpdate, Tasks: undo_tasks})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Update task in transaction, tasks changed along with it are returned so the update can be undone
+func (t *taskRepository) update(tx *gorm.DB, id int32, user_id int32, new_info *domain.Task, tags_add []int32, tags_remove []int32, force bool) (*domain.Task, domain.UndoTasks, error) {
 	new_task_map := map[string]any{}
 	new_task_map["name"] = new_info.Name
 	new_task_map["description"] = new_info.Description
-	new_task_map["is_done"] = new_info.IsDone
 	new_task_map["creator_id"] = new_info.CreatorId
+	new_task_map["due_at"] = new_info.DueAt
+	new_task_map["due_all_day"] = new_info.DueAllDay
+	new_task_map["due_timezone"] = new_info.DueTimezone
+	new_task_map["priority"] = new_info.Priority
+	new_task_map["assignee_id"] = new_info.AssigneeId
+	new_task_map["project_id"] = new_info.ProjectId
+	new_task_map["version"] = gorm.Expr("version + 1")
 
-	// Update information
-	if err := t.Conn.Db.First(&new_info, id).Updates(new_task_map).Error; err != nil {
-		return nil, err
+	var task domain.Task
+	previous, err := lockTask(tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Tags are only compared in history when they are changed
+	tags_changed := len(tags_add) != 0 || len(tags_remove) != 0
+	if tags_changed {
+		previous.Tags = []tagDomain.Tag{}
+		if err := tx.Model(previous).Association("Tags").Find(&previous.Tags); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	permission, err := getPermission(tx, id, user_id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case permission >= domain.PermissionEditor:
+		// Only owner can hand task over to another creator
+		if permission < domain.PermissionOwner || new_info.CreatorId == 0 {
+			delete(new_task_map, "creator_id")
+		}
+	case previous.AssigneeId != nil && *previous.AssigneeId == user_id:
+		// Assignee can only change status of task
+		if !onlyStatusChanged(previous, new_info) || tags_changed {
+			return nil, nil, domain.ErrPermissionDenied
+		}
+		new_task_map = map[string]any{
+			"version": gorm.Expr("version + 1"),
+		}
+	default:
+		return nil, nil, domain.ErrPermissionDenied
+	}
+
+	// Only owner of project can move tasks into it
+	if new_info.ProjectId != nil && (previous.ProjectId == nil || *previous.ProjectId != *new_info.ProjectId) {
+		if err := requireProject(tx, *new_info.ProjectId, user_id); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	status := new_info.Status
+	if status == domain.StatusUnspecified {
+		status = previous.Status.FromIsDone(new_info.IsDone)
+	}
+	if !previous.Status.CanChangeTo(status) {
+		return nil, nil, fmt.Errorf("%w: %v to %v", domain.ErrStatusTransition, previous.Status, status)
+	}
+
+	now := time.Now()
+	new_task_map["status"] = status
+	new_task_map["is_done"] = status.IsDone()
+	if status != previous.Status {
+		new_task_map["status_changed_at"] = now
+		new_task_map["status_changed_by"] = user_id
+		if status.IsDone() {
+			new_task_map["done_at"] = now
+		}
+	}
+
+	done_now := status.IsDone() && !previous.Status.IsDone()
+	if done_now && !force {
+		var blocked int64
+		if err := tx.Model(&domain.Task{}).Where("id = ?", id).Where(openBlockersCondition, domain.ClosedStatuses).Count(&blocked).Error; err != nil {
+			return nil, nil, err
+		}
+		if blocked != 0 {
+			return nil, nil, domain.ErrTaskBlocked
+		}
+	}
+	var children []domain.Task
+	if done_now {
+		if children, err = t.completeChildren(tx, id, user_id, now, force); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Check version and update information in one statement
+	query := tx.Model(&task).Clauses(clause.Returning{}).Where("id = ?", id)
+	if new_info.Version != 0 {
+		query = query.Where("version = ?", new_info.Version)
+	}
+
+	result := query.Updates(new_task_map)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil, checkVersion(tx, id)
+	}
+
+	if status != previous.Status {
+		if err := tx.Omit("Task").Create(&domain.StatusChange{
+			TaskId:     id,
+			FromStatus: previous.Status,
+			ToStatus:   status,
+			ChangedBy:  user_id,
+			ChangedAt:  now,
+		}).Error; err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// Update tags
@@ -129,46 +433,200 @@ func (t *taskRepository) Update(ctx context.Context, id int32, new_info *domain.
 		return tags
 	}
 
-	if err := t.Conn.Db.Model(&new_info).Association("Tags").Append(tranferIdToTag(tags_add)); err != nil {
-		return nil, err
+	if err := tx.Model(&task).Association("Tags").Append(tranferIdToTag(tags_add)); err != nil {
+		return nil, nil, err
 	}
-	if err := t.Conn.Db.Model(&new_info).Association("Tags").Delete(tranferIdToTag(tags_remove)); err != nil {
-		return nil, err
+	if err := tx.Model(&task).Association("Tags").Delete(tranferIdToTag(tags_remove)); err != nil {
+		return nil, nil, err
+	}
+	if tags_changed {
+		task.Tags = []tagDomain.Tag{}
+		if err := tx.Model(&task).Association("Tags").Find(&task.Tags); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := recordHistory(tx, id, user_id, domain.HistoryUpdated, domain.DiffTasks(previous, &task)); err != nil {
+		return nil, nil, err
 	}
 
-	// TODO: implement new_info here
-	return new_info, nil
+	// Update is undone by bringing back previous state of task and its completed children
+	undo_tasks := domain.UndoTasks{{TaskId: id, Version: task.Version, Previous: domain.NewTaskState(previous)}}
+	for i := range children {
+		undo_tasks = append(undo_tasks, domain.UndoTask{TaskId: children[i].ID, Version: children[i].Version + 1, Previous: domain.NewTaskState(&children[i])})
+	}
+
+	// Done occurrence of a series brings the next one, children done along with task included
+	done_tasks := []*domain.Task{}
+	if done_now {
+		done_tasks = append(done_tasks, &task)
+	}
+	for i := range children {
+		done_tasks = append(done_tasks, &children[i])
+	}
+	for _, done_task := range done_tasks {
+		if done_task.SeriesId == nil {
+			continue
+		}
+		next, err := createNextOccurrence(tx, done_task, user_id, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if next != nil {
+			undo_tasks = append(undo_tasks, domain.UndoTask{TaskId: next.ID, Version: next.Version, Created: true})
+		}
+	}
+
+	return &task, undo_tasks, nil
 }
 
-func (t *taskRepository) Delete(ctx context.Context, ids []int32) error {
+// Move tasks to trash along with their subtasks, they are purged after retention period
+func (t *taskRepository) Delete(ctx context.Context, user_id int32, ids []int32, versions map[int32]int32) error {
 	if len(ids) == 0 {
 		return nil
 	}
 
-	// Delete tasks
-	// Add Select("Tags") to delete association of task and tag
-	if err := t.Conn.Db.Select("Tags").Delete(&domain.Task{}, ids).Error; err != nil {
+	return t.transaction(func(tx *gorm.DB) error {
+		return trashTasks(tx, user_id, ids, versions)
+	})
+}
+
+// Trash tasks with their subtasks and record history and undo of the delete
+func trashTasks(tx *gorm.DB, user_id int32, ids []int32, versions map[int32]int32) error {
+	// Every existing task must be owned by user
+	condition, args := permissionCondition(user_id, domain.PermissionOwner)
+	var not_owned int64
+	if err := tx.Model(&domain.Task{}).Where("id IN ?", ids).Not(condition, args...).Count(&not_owned).Error; err != nil {
 		return err
 	}
+	if not_owned != 0 {
+		return domain.ErrPermissionDenied
+	}
 
-	return nil
+	// Subtasks share time of their parent, so they are restored together
+	now := time.Now()
+	trashed_ids := []int32{}
+	unchecked_ids := []int32{}
+	for _, id := range ids {
+		version, ok := versions[id]
+		if !ok || version == 0 {
+			unchecked_ids = append(unchecked_ids, id)
+			continue
+		}
+
+		// Check version and move to trash in one statement
+		var returned_ids []int32
+		err := tx.Raw("UPDATE tasks SET deleted_at = ? WHERE id = ? AND version = ? AND deleted_at IS NULL RETURNING id", now, id, version).Scan(&returned_ids).Error
+		if err != nil {
+			return err
+		}
+		if len(returned_ids) == 0 {
+			return checkVersion(tx, id)
+		}
+		trashed_ids = append(trashed_ids, returned_ids...)
+	}
+
+	if len(unchecked_ids) != 0 {
+		var returned_ids []int32
+		err := tx.Raw("UPDATE tasks SET deleted_at = ? WHERE id IN ? AND deleted_at IS NULL RETURNING id", now, unchecked_ids).Scan(&returned_ids).Error
+		if err != nil {
+			return err
+		}
+		trashed_ids = append(trashed_ids, returned_ids...)
+	}
+
+	// Delete is undone by restoring given tasks, their subtasks are restored with them
+	undo := &domain.UndoOperation{
+		UserId:    user_id,
+		Kind:      domain.UndoDelete,
+		Tasks:     domain.UndoTasks{},
+		TrashedAt: &now,
+	}
+	for _, trashed_id := range trashed_ids {
+		undo.Tasks = append(undo.Tasks, domain.UndoTask{TaskId: trashed_id})
+	}
+
+	var descendant_ids []int32
+	err := tx.Raw(`WITH RECURSIVE descendants AS (
+			SELECT id FROM tasks WHERE parent_id IN ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT tasks.id FROM tasks JOIN descendants ON tasks.parent_id = descendants.id WHERE tasks.deleted_at IS NULL
+		)
+		UPDATE tasks SET deleted_at = ? WHERE id IN (SELECT id FROM descendants) RETURNING id`, ids, now).Scan(&descendant_ids).Error
+	if err != nil {
+		return err
+	}
+	trashed_ids = append(trashed_ids, descendant_ids...)
+
+	if err := recordHistoryOfTasks(tx, trashed_ids, user_id, domain.HistoryDeleted); err != nil {
+		return err
+	}
+
+	if len(undo.Tasks) == 0 {
+		return nil
+	}
+	return recordUndo(tx, undo)
 }
 
-func (t *taskRepository) IsExists(ctx context.Context, ids int32) (bool, error) {
-	// Find by IDs
-	var tasks []domain.Task
-	if err := t.Conn.Db.Where("Id IN ?", ids).Find(&tasks).Error; err != nil {
-		return false, err
+// Check nothing but status is different from previous state of task
+func onlyStatusChanged(previous *domain.Task, new_info *domain.Task) bool {
+	sameId := func(a *int32, b *int32) bool {
+		return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+	}
+	sameDue := (previous.DueAt == nil && new_info.DueAt == nil) ||
+		(previous.DueAt != nil && new_info.DueAt != nil && previous.DueAt.Equal(*new_info.DueAt) && previous.DueAllDay == new_info.DueAllDay)
+
+	return previous.Name == new_info.Name &&
+		previous.Description == new_info.Description &&
+		previous.Priority == new_info.Priority &&
+		sameDue &&
+		sameId(previous.AssigneeId, new_info.AssigneeId) &&
+		sameId(previous.ProjectId, new_info.ProjectId)
+}
+
+// Lock task row until the end of transaction and read its current state
+func lockTask(tx *gorm.DB, id int32) (*domain.Task, error) {
+	var task domain.Task
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTaskNotExists
+		}
+		return nil, err
 	}
 
-	// Check length of tasks found is equal ids
-	if len(tasks) != 1 {
+	return &task, nil
+}
+
+// Find why a conditional statement did not touch the task
+func checkVersion(tx *gorm.DB, id int32) error {
+	var count int64
+	if err := tx.Model(&domain.Task{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return domain.ErrTaskNotExists
+	}
+	return domain.ErrTaskVersionMismatch
+}
+
+// Task which user can not view does not exist for user
+func (t *taskRepository) IsExists(ctx context.Context, id int32, user_id int32) (bool, error) {
+	err := requirePermission(t.Conn.Db, id, user_id, domain.PermissionViewer)
+	if errors.Is(err, domain.ErrTaskNotExists) || errors.Is(err, domain.ErrPermissionDenied) {
 		return false, nil
 	}
+	if err != nil {
+		return false, err
+	}
 
 	return true, nil
 }
 
+func (t *taskRepository) CheckPermission(ctx context.Context, id int32, user_id int32, level domain.Permission) error {
+	return requirePermission(t.Conn.Db, id, user_id, level)
+}
+
 func (t *taskRepository) GetByUserId(ctx context.Context, user_id int32) ([]int32, error) {
 	return nil, errors.New("Implemented needed")
 }
@@ -0,0 +1,95 @@
+package postgre
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+	userDomain "todo-go-grpc/app/user/domain"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Repository on a schema of its own, so tests do not see rows of each other.
+// Tests which need Postgres are skipped unless TEST_DATABASE_URL is set
+func newTestRepository(t *testing.T, config repository.Config) (*taskRepository, *gorm.DB) {
+	base_url := os.Getenv("TEST_DATABASE_URL")
+	if base_url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	gorm_config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	base, err := gorm.Open(postgres.Open(base_url), gorm_config)
+	if err != nil {
+		t.Fatalf("open database error: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := base.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema error: %v", err)
+	}
+	t.Cleanup(func() {
+		base.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sql_db, err := base.DB(); err == nil {
+			sql_db.Close()
+		}
+	})
+
+	schema_url, err := url.Parse(base_url)
+	if err != nil {
+		t.Fatalf("parse TEST_DATABASE_URL error: %v", err)
+	}
+	query := schema_url.Query()
+	query.Set("search_path", schema)
+	schema_url.RawQuery = query.Encode()
+
+	db, err := gorm.Open(postgres.Open(schema_url.String()), gorm_config)
+	if err != nil {
+		t.Fatalf("open schema error: %v", err)
+	}
+	t.Cleanup(func() {
+		if sql_db, err := db.DB(); err == nil {
+			sql_db.Close()
+		}
+	})
+	if err := dbservice.Migrate(db); err != nil {
+		t.Fatalf("Migrate() error: %v", err)
+	}
+
+	if config.MaxDepth == 0 {
+		config.MaxDepth = 5
+	}
+	if config.UndoWindow == 0 {
+		config.UndoWindow = time.Hour
+	}
+	return &taskRepository{Conn: dbservice.Database{Db: db}, Config: config}, db
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, username string) int32 {
+	user := userDomain.User{Username: username, Password: "password", Name: username}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user error: %v", err)
+	}
+	return user.ID
+}
+
+func createTestTask(t *testing.T, db *gorm.DB, task domain.Task) *domain.Task {
+	prepareCreate(task.CreatorId, &task)
+	if err := db.Create(&task).Error; err != nil {
+		t.Fatalf("create task error: %v", err)
+	}
+	return &task
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string, query string, args ...any) int64 {
+	var count int64
+	if err := db.Table(table).Where(query, args...).Count(&count).Error; err != nil {
+		t.Fatalf("count %s error: %v", table, err)
+	}
+	return count
+}
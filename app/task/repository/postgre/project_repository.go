@@ -0,0 +1,135 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type projectRepository struct {
+	Conn dbservice.Database
+}
+
+func NewProjectRepository(conn dbservice.Database) repository.ProjectRepository {
+	return &projectRepository{
+		Conn: conn,
+	}
+}
+
+// Select number of open and done tasks along with project
+func withTaskCounts(tx *gorm.DB) *gorm.DB {
+	return tx.Select(`projects.*,
+		(SELECT count(*) FROM tasks WHERE tasks.project_id = projects.id AND tasks.status NOT IN ? AND tasks.deleted_at IS NULL) AS open_count,
+		(SELECT count(*) FROM tasks WHERE tasks.project_id = projects.id AND tasks.is_done AND tasks.deleted_at IS NULL) AS done_count`, domain.ClosedStatuses)
+}
+
+func (p *projectRepository) Fetch(ctx context.Context, user_id int32, include_archived bool) ([]domain.Project, error) {
+	var projects []domain.Project
+	tx := withTaskCounts(p.Conn.Db).Where("owner_id = ?", user_id)
+	if !include_archived {
+		tx = tx.Where("archived = false")
+	}
+
+	if err := tx.Order("id asc").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (p *projectRepository) GetByID(ctx context.Context, id int32, user_id int32) (*domain.Project, error) {
+	var project domain.Project
+	if err := withTaskCounts(p.Conn.Db).First(&project, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrProjectNotExists
+		}
+		return nil, err
+	}
+	if project.OwnerId != user_id {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	return &project, nil
+}
+
+func (p *projectRepository) Create(ctx context.Context, info *domain.Project) (*domain.Project, error) {
+	if err := p.Conn.Db.Create(info).Error; err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (p *projectRepository) Update(ctx context.Context, id int32, user_id int32, new_info *domain.Project) (*domain.Project, error) {
+	new_info_map := map[string]any{}
+	new_info_map["name"] = new_info.Name
+	new_info_map["description"] = new_info.Description
+	new_info_map["color"] = new_info.Color
+	new_info_map["archived"] = new_info.Archived
+	new_info_map["version"] = gorm.Expr("version + 1")
+
+	// Check owner, version and update in one statement
+	var project domain.Project
+	query := p.Conn.Db.Model(&project).Clauses(clause.Returning{}).Where("id = ? AND owner_id = ?", id, user_id)
+	if new_info.Version != 0 {
+		query = query.Where("version = ?", new_info.Version)
+	}
+
+	result := query.Updates(new_info_map)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, p.checkProject(id, user_id)
+	}
+
+	return &project, nil
+}
+
+// Tasks of deleted project stay without project
+func (p *projectRepository) Delete(ctx context.Context, id int32, user_id int32, version int32) error {
+	query := p.Conn.Db.Where("id = ? AND owner_id = ?", id, user_id)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&domain.Project{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return p.checkProject(id, user_id)
+	}
+
+	return nil
+}
+
+// Find why a conditional statement did not touch the project
+func (p *projectRepository) checkProject(id int32, user_id int32) error {
+	if err := requireProject(p.Conn.Db, id, user_id); err != nil {
+		return err
+	}
+
+	return domain.ErrProjectVersionMismatch
+}
+
+// Check project exists and belongs to user
+func requireProject(tx *gorm.DB, id int32, user_id int32) error {
+	var project domain.Project
+	if err := tx.Select("id", "owner_id").First(&project, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrProjectNotExists
+		}
+		return err
+	}
+	if project.OwnerId != user_id {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
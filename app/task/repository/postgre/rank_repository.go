@@ -0,0 +1,162 @@
+package postgre
+
+import (
+	"fmt"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+)
+
+// Ranks are compared byte by byte whatever collation of database is
+const rankOrder = `rank COLLATE "C"`
+
+// List of tasks ordered by hand, key names the list in rank lock
+type rankedList struct {
+	key   string
+	query func() *gorm.DB
+}
+
+// Get tasks of list which is ordered by hand: children of parent,
+// top-level tasks of project or top-level tasks out of project of creator
+func rankList(tx *gorm.DB, parent_id *int32, project_id *int32, creator_id int32) rankedList {
+	key := fmt.Sprintf("creator %d", creator_id)
+	if parent_id != nil {
+		key = fmt.Sprintf("parent %d", *parent_id)
+	} else if project_id != nil {
+		key = fmt.Sprintf("project %d", *project_id)
+	}
+
+	return rankedList{
+		key: key,
+		query: func() *gorm.DB {
+			query := tx.Model(&domain.Task{})
+			if parent_id != nil {
+				return query.Where("parent_id = ?", *parent_id)
+			}
+			query = query.Where("parent_id IS NULL")
+			if project_id != nil {
+				return query.Where("project_id = ?", *project_id)
+			}
+			return query.Where("project_id IS NULL AND creator_id = ?", creator_id)
+		},
+	}
+}
+
+// Get rank of the last task of list, empty when list has no task
+func lastRank(list rankedList, id int32) (string, error) {
+	var ranks []string
+	err := list.query().Where("id <> ?", id).Order(rankOrder+" desc").Order("id desc").Limit(1).Pluck("rank", &ranks).Error
+	if err != nil || len(ranks) == 0 {
+		return "", err
+	}
+	return ranks[0], nil
+}
+
+// Get rank of neighbour which must be in list
+func neighbourRank(list rankedList, neighbour_id int32) (string, error) {
+	var ranks []string
+	if err := list.query().Where("id = ?", neighbour_id).Pluck("rank", &ranks).Error; err != nil {
+		return "", err
+	}
+	if len(ranks) == 0 {
+		return "", domain.ErrRankNeighbour
+	}
+	return ranks[0], nil
+}
+
+// Get ranks which task is placed between, false when they are not in order and list has to be rebalanced
+func neighbourRanks(list rankedList, id int32, before_id int32, after_id int32) (prev string, next string, ok bool, err error) {
+	if before_id != 0 {
+		if prev, err = neighbourRank(list, before_id); err != nil {
+			return "", "", false, err
+		}
+	}
+	if after_id != 0 {
+		if next, err = neighbourRank(list, after_id); err != nil {
+			return "", "", false, err
+		}
+		return prev, next, prev < next, nil
+	}
+	if before_id == 0 {
+		prev, err = lastRank(list, id)
+		return prev, "", true, err
+	}
+
+	// Task goes between neighbour before it and task which follows that neighbour now
+	var ranks []string
+	err = list.query().Where("id NOT IN ? AND "+rankOrder+" >= ?", []int32{id, before_id}, prev).
+		Order(rankOrder+" asc").Order("id asc").Limit(1).Pluck("rank", &ranks).Error
+	if err != nil || len(ranks) == 0 {
+		return prev, "", true, err
+	}
+	return prev, ranks[0], prev < ranks[0], nil
+}
+
+// Give tasks of list evenly spaced ranks, order of tasks is kept
+func rebalanceRanks(tx *gorm.DB, list rankedList) error {
+	var ids []int32
+	if err := list.query().Order(rankOrder+" asc").Order("id asc").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	for i, rank := range domain.SpreadRanks(len(ids)) {
+		if err := tx.Model(&domain.Task{}).Where("id = ?", ids[i]).Update("rank", rank).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Find rank of task placed between neighbours in list, task is placed at the end of list when no neighbour is given
+func placeTask(tx *gorm.DB, list rankedList, id int32, before_id int32, after_id int32) (string, error) {
+	// List is not rebalanced while a task is placed in it
+	if err := lock(tx, rankLock, list.key); err != nil {
+		return "", err
+	}
+
+	for rebalanced := false; ; rebalanced = true {
+		prev, next, ok, err := neighbourRanks(list, id, before_id, after_id)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			if rank, ok := domain.RankBetween(prev, next); ok {
+				return rank, nil
+			}
+		}
+		// Ranks of rebalanced list are all different, so neighbours are given in wrong order
+		if rebalanced {
+			return "", fmt.Errorf("%w: task before must precede task after", domain.ErrRankNeighbour)
+		}
+
+		if err := rebalanceRanks(tx, list); err != nil {
+			return "", err
+		}
+	}
+}
+
+// Find ranks of count tasks appended to the end of list one after another, they are spread over space after the last task
+func appendRanks(tx *gorm.DB, list rankedList, count int) ([]string, error) {
+	if err := lock(tx, rankLock, list.key); err != nil {
+		return nil, err
+	}
+
+	for rebalanced := false; ; rebalanced = true {
+		prev, err := lastRank(list, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if ranks, ok := domain.RanksAfter(prev, count); ok {
+			return ranks, nil
+		}
+		if rebalanced {
+			return nil, fmt.Errorf("%w: %d tasks do not fit at the end of list", domain.ErrRankNeighbour, count)
+		}
+
+		if err := rebalanceRanks(tx, list); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+	tagDomain "todo-go-grpc/app/tag/domain"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Create next occurrence of series after task on behalf of user, nothing is created when series is stopped or ended
+func createNextOccurrence(tx *gorm.DB, task *domain.Task, user_id int32, done_at time.Time) (*domain.Task, error) {
+	var series domain.Series
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&series, *task.SeriesId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSeriesNotExists
+		}
+		return nil, err
+	}
+	if series.StoppedAt != nil {
+		return nil, nil
+	}
+
+	recurrence, err := domain.ParseRecurrence(series.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(task.DueTimezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	base := done_at
+	if series.Anchor == domain.RecurrenceFromDueDate && task.DueAt != nil {
+		base = *task.DueAt
+	}
+	next := recurrence.Next(base.In(location))
+	if task.DueAllDay {
+		next = time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, location)
+	}
+	if recurrence.Ended(next, series.Occurrences) {
+		return nil, nil
+	}
+
+	var tags []tagDomain.Tag
+	if err := tx.Model(task).Association("Tags").Find(&tags); err != nil {
+		return nil, err
+	}
+
+	next_task := domain.Task{
+		Name:        task.Name,
+		Description: task.Description,
+		CreatorId:   task.CreatorId,
+		Priority:    task.Priority,
+		Status:      domain.StatusTodo,
+		ParentId:    task.ParentId,
+		SeriesId:    task.SeriesId,
+		AssigneeId:  task.AssigneeId,
+		ProjectId:   task.ProjectId,
+		// Next occurrence takes place of task in manual order
+		Rank:        task.Rank,
+		DueAt:       &next,
+		DueAllDay:   task.DueAllDay,
+		DueTimezone: location.String(),
+		Tags:        tags,
+	}
+	if err := tx.Create(&next_task).Error; err != nil {
+		return nil, err
+	}
+	if err := recordHistory(tx, next_task.ID, user_id, domain.HistoryCreated, domain.DiffTasks(nil, &next_task)); err != nil {
+		return nil, err
+	}
+
+	// Reminders relative to due date follow the series
+	if err := tx.Exec(`INSERT INTO reminders (task_id, offset_seconds, created_at)
+		SELECT ?, offset_seconds, ? FROM reminders WHERE task_id = ? AND remind_at IS NULL`, next_task.ID, time.Now(), task.ID).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Model(&series).Update("occurrences", gorm.Expr("occurrences + 1")).Error; err != nil {
+		return nil, err
+	}
+
+	return &next_task, nil
+}
+
+func (t *taskRepository) Skip(ctx context.Context, id int32, user_id int32, version int32) (*domain.Task, error) {
+	var next *domain.Task
+	err := t.transaction(func(tx *gorm.DB) error {
+		task, err := lockTask(tx, id)
+		if err != nil {
+			return err
+		}
+		// Skipped occurrence is deleted, only owner can do it
+		if err := requirePermission(tx, id, user_id, domain.PermissionOwner); err != nil {
+			return err
+		}
+		if task.SeriesId == nil {
+			return domain.ErrTaskNotRecurring
+		}
+
+		if next, err = createNextOccurrence(tx, task, user_id, time.Now()); err != nil {
+			return err
+		}
+
+		// Skipped occurrence goes to trash like any deleted task,
+		// its attachments are removed when it is purged
+		return trashTasks(tx, user_id, []int32{id}, map[int32]int32{id: version})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// Check user is editor of any task of series
+func requireSeriesPermission(tx *gorm.DB, id int32, user_id int32) error {
+	condition, args := permissionCondition(user_id, domain.PermissionEditor)
+
+	var count int64
+	if err := tx.Model(&domain.Task{}).Where("series_id = ?", id).Where(condition, args...).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != 0 {
+		return nil
+	}
+
+	if err := tx.Select("id").First(&domain.Series{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrSeriesNotExists
+		}
+		return err
+	}
+
+	return domain.ErrPermissionDenied
+}
+
+// Lock series against concurrent changes of its rule
+func lockSeries(tx *gorm.DB, id int32) (*domain.Series, error) {
+	var series domain.Series
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrSeriesNotExists
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// Change of series is recorded in history of every its task
+func recordSeriesHistory(tx *gorm.DB, id int32, user_id int32, changes domain.FieldChanges) error {
+	var task_ids []int32
+	if err := tx.Model(&domain.Task{}).Where("series_id = ?", id).Order("id asc").Pluck("id", &task_ids).Error; err != nil {
+		return err
+	}
+
+	for _, task_id := range task_ids {
+		if err := recordHistory(tx, task_id, user_id, domain.HistoryUpdated, changes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *taskRepository) UpdateSeries(ctx context.Context, id int32, user_id int32, rule string, anchor domain.RecurrenceAnchor) (*domain.Series, error) {
+	var series *domain.Series
+	err := t.transaction(func(tx *gorm.DB) error {
+		var err error
+		if series, err = lockSeries(tx, id); err != nil {
+			return err
+		}
+		if err := requireSeriesPermission(tx, id, user_id); err != nil {
+			return err
+		}
+
+		changes := domain.FieldChanges{}
+		if series.Rule != rule {
+			changes["series_rule"] = domain.FieldChange{Before: series.Rule, After: rule}
+		}
+		if series.Anchor != anchor {
+			changes["series_anchor"] = domain.FieldChange{Before: series.Anchor, After: anchor}
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(series).Updates(map[string]any{
+			"rule":   rule,
+			"anchor": anchor,
+		}).Error; err != nil {
+			return err
+		}
+
+		return recordSeriesHistory(tx, id, user_id, changes)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func (t *taskRepository) StopSeries(ctx context.Context, id int32, user_id int32) (*domain.Series, error) {
+	var series *domain.Series
+	err := t.transaction(func(tx *gorm.DB) error {
+		var err error
+		if series, err = lockSeries(tx, id); err != nil {
+			return err
+		}
+		if err := requireSeriesPermission(tx, id, user_id); err != nil {
+			return err
+		}
+
+		// Stopping a stopped series keeps its first stopped time
+		if series.StoppedAt != nil {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(series).Update("stopped_at", now).Error; err != nil {
+			return err
+		}
+
+		changes := domain.FieldChanges{"series_stopped_at": {Before: nil, After: now}}
+		return recordSeriesHistory(tx, id, user_id, changes)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
@@ -0,0 +1,147 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reminderRepository struct {
+	Conn dbservice.Database
+}
+
+func NewReminderRepository(conn dbservice.Database) repository.ReminderRepository {
+	return &reminderRepository{
+		Conn: conn,
+	}
+}
+
+func (r *reminderRepository) FetchByTask(ctx context.Context, task_id int32) ([]domain.Reminder, error) {
+	var reminders []domain.Reminder
+	if err := r.Conn.Db.Where("task_id = ?", task_id).Order("id asc").Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+func (r *reminderRepository) Create(ctx context.Context, info *domain.Reminder) (*domain.Reminder, error) {
+	if err := r.Conn.Db.Omit("Task").Create(info).Error; err != nil {
+		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+			if pgError.Code == "23503" {
+				return nil, domain.ErrTaskNotExists
+			}
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Editor of task of reminder
+func reminderEditorCondition(user_id int32) (string, []any) {
+	condition, args := permissionCondition(user_id, domain.PermissionEditor)
+	return "EXISTS (SELECT 1 FROM tasks WHERE tasks.id = reminders.task_id AND " + condition + ")", args
+}
+
+// Snoozed reminder fires again at until, even when it has fired before
+func (r *reminderRepository) Snooze(ctx context.Context, id int32, user_id int32, until time.Time) (*domain.Reminder, error) {
+	var reminder domain.Reminder
+	condition, args := reminderEditorCondition(user_id)
+	result := r.Conn.Db.Model(&reminder).Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Where(condition, args...).
+		Updates(map[string]any{
+			"snoozed_until": until,
+			"fired_at":      nil,
+		})
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, r.checkReminder(id)
+	}
+
+	return &reminder, nil
+}
+
+func (r *reminderRepository) Delete(ctx context.Context, id int32, user_id int32) error {
+	condition, args := reminderEditorCondition(user_id)
+	result := r.Conn.Db.Where(condition, args...).Delete(&domain.Reminder{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.checkReminder(id)
+	}
+
+	return nil
+}
+
+// Find why a statement with editor condition did not touch the reminder
+func (r *reminderRepository) checkReminder(id int32) error {
+	if err := r.Conn.Db.Select("id").First(&domain.Reminder{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrReminderNotExists
+		}
+		return err
+	}
+
+	return domain.ErrPermissionDenied
+}
+
+// Each reminder is claimed, fired and marked in its own transaction, so a failure later in the batch
+// can not roll back a reminder which is already sent
+func (r *reminderRepository) FireDue(ctx context.Context, now time.Time, limit int, fire func(reminder domain.Reminder) error) (int, error) {
+	fired := 0
+	// Failed reminders are left for the next round
+	failed_ids := []int32{0}
+	for attempt := 0; attempt < limit; attempt++ {
+		found := false
+		err := r.Conn.Db.Transaction(func(tx *gorm.DB) error {
+			// Rows claimed by another instance are skipped, so a reminder never fires twice
+			var reminders []domain.Reminder
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "reminders"}, Options: "SKIP LOCKED"}).
+				Joins("JOIN tasks ON tasks.id = reminders.task_id").
+				Where("reminders.fired_at IS NULL AND tasks.status NOT IN ? AND tasks.deleted_at IS NULL", domain.ClosedStatuses).
+				Where("COALESCE(reminders.snoozed_until, reminders.remind_at, tasks.due_at - reminders.offset_seconds * interval '1 second') <= ?", now).
+				Where("reminders.id NOT IN ?", failed_ids).
+				Preload("Task").
+				Order("reminders.id asc").
+				Limit(1).
+				Find(&reminders).Error
+			if err != nil || len(reminders) == 0 {
+				return err
+			}
+			found = true
+
+			reminder := reminders[0]
+			if err := fire(reminder); err != nil {
+				failed_ids = append(failed_ids, reminder.ID)
+				return nil
+			}
+			if err := tx.Model(&reminder).Update("fired_at", now).Error; err != nil {
+				return err
+			}
+			fired++
+			return nil
+		})
+
+		if err != nil {
+			return fired, err
+		}
+		if !found {
+			break
+		}
+	}
+
+	return fired, nil
+}
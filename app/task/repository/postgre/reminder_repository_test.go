@@ -0,0 +1,75 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+func TestReminderPermission(t *testing.T) {
+	_, db := newTestRepository(t, repository.Config{})
+	repo := &reminderRepository{Conn: dbservice.Database{Db: db}}
+	ctx := context.Background()
+	owner_id := createTestUser(t, db, "owner")
+	viewer_id := createTestUser(t, db, "viewer")
+	editor_id := createTestUser(t, db, "editor")
+
+	parent := createTestTask(t, db, domain.Task{Name: "parent", CreatorId: owner_id})
+	task := createTestTask(t, db, domain.Task{Name: "task", CreatorId: owner_id, ParentId: &parent.ID})
+	shares := []domain.Share{
+		{TaskId: parent.ID, UserId: viewer_id, Permission: domain.PermissionViewer},
+		{TaskId: parent.ID, UserId: editor_id, Permission: domain.PermissionEditor},
+	}
+	if err := db.Omit("Task").Create(&shares).Error; err != nil {
+		t.Fatalf("create shares error: %v", err)
+	}
+	remind_at := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		user_id int32
+		missing bool
+		want    error
+	}{
+		{name: "owner of ancestor", user_id: owner_id, want: nil},
+		{name: "editor through ancestor", user_id: editor_id, want: nil},
+		{name: "viewer through ancestor", user_id: viewer_id, want: domain.ErrPermissionDenied},
+		{name: "missing reminder", user_id: owner_id, missing: true, want: domain.ErrReminderNotExists},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reminder, err := repo.Create(ctx, &domain.Reminder{TaskId: task.ID, RemindAt: &remind_at})
+			if err != nil {
+				t.Fatalf("Create() error: %v", err)
+			}
+			id := reminder.ID
+			if test.missing {
+				id += 1000
+			}
+
+			snoozed, err := repo.Snooze(ctx, id, test.user_id, remind_at.Add(time.Hour))
+			if !errors.Is(err, test.want) {
+				t.Fatalf("Snooze() error = %v, want %v", err, test.want)
+			}
+			if err == nil && (snoozed.SnoozedUntil == nil || snoozed.FiredAt != nil) {
+				t.Fatalf("Snooze() = %+v, want snoozed reminder", snoozed)
+			}
+
+			if err := repo.Delete(ctx, id, test.user_id); !errors.Is(err, test.want) {
+				t.Fatalf("Delete() error = %v, want %v", err, test.want)
+			}
+			want_remaining := int64(1)
+			if test.want == nil {
+				want_remaining = 0
+			}
+			if got := countRows(t, db, "reminders", "id = ?", reminder.ID); got != want_remaining {
+				t.Fatalf("%d reminders left after Delete(), want %d", got, want_remaining)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package postgre
+
+import (
+	"fmt"
+	"regexp"
+	"todo-go-grpc/app/dbservice"
+)
+
+// Matched words are wrapped in <b></b>, description is cut to fragments around them
+const searchHeadlineOptions = "StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=20, MinWords=5"
+
+// Language is put into statements as it is, so index on the same expression can be used
+var searchLanguageRegexp = regexp.MustCompile(`^[a-z_]+$`)
+
+// Words of name weigh more than words of description
+func searchVector(language string) string {
+	return fmt.Sprintf("(setweight(to_tsvector('%s', coalesce(tasks.name, '')), 'A') || setweight(to_tsvector('%s', coalesce(tasks.description, '')), 'B'))", language, language)
+}
+
+// Create index used by full-text search in language, each language has its own index
+func CreateSearchIndex(conn dbservice.Database, language string) error {
+	if !searchLanguageRegexp.MatchString(language) {
+		return fmt.Errorf("search language %q is not a valid text search configuration name", language)
+	}
+
+	return conn.Db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_tasks_search_%s ON tasks USING GIN (%s)", language, searchVector(language))).Error
+}
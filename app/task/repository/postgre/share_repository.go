@@ -0,0 +1,151 @@
+package postgre
+
+import (
+	"context"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Effective permission of user on task, it is the highest one given by creating, being assigned or
+// being shared the task or any of its ancestors. Statement using it must select from tasks,
+// it is only used for one task at a time since it walks ancestors of each row
+const permissionExpr = `(WITH RECURSIVE ancestors AS (
+		SELECT tasks.id, tasks.parent_id, tasks.creator_id
+		UNION
+		SELECT parent.id, parent.parent_id, parent.creator_id FROM tasks AS parent JOIN ancestors ON parent.id = ancestors.parent_id
+	)
+	SELECT GREATEST(
+		MAX(CASE WHEN ancestors.creator_id = ? THEN ? ELSE 0 END),
+		CASE WHEN tasks.assignee_id = ? THEN ? ELSE 0 END,
+		COALESCE(MAX(shares.permission), 0)
+	) FROM ancestors LEFT JOIN shares ON shares.task_id = ancestors.id AND shares.user_id = ?)`
+
+func permissionArgs(user_id int32) []any {
+	return []any{user_id, domain.PermissionOwner, user_id, domain.PermissionViewer, user_id}
+}
+
+// Tasks user has any permission on along with the highest one, the same as permissionExpr gives for each of them.
+// Set is built once per statement from what user created, is shared and is assigned, then grants go down to descendants
+const accessibleTasks = `(WITH RECURSIVE granted AS (
+		SELECT id AS task_id, ?::integer AS permission FROM tasks WHERE creator_id = ?
+		UNION ALL
+		SELECT task_id, permission FROM shares WHERE user_id = ?
+	), inherited AS (
+		SELECT task_id, permission FROM granted
+		UNION ALL
+		SELECT tasks.id, inherited.permission FROM tasks JOIN inherited ON tasks.parent_id = inherited.task_id
+	)
+	SELECT task_id, MAX(permission) AS permission FROM (
+		SELECT task_id, permission FROM inherited
+		UNION ALL
+		SELECT id, ?::integer FROM tasks WHERE assignee_id = ?
+	) AS grants GROUP BY task_id)`
+
+func accessibleArgs(user_id int32) []any {
+	return []any{domain.PermissionOwner, user_id, user_id, domain.PermissionViewer, user_id}
+}
+
+// Join permission of user on tasks as access.permission, it is null when user has none
+func withAccess(tx *gorm.DB, user_id int32) *gorm.DB {
+	return tx.Joins("LEFT JOIN "+accessibleTasks+" AS access ON access.task_id = tasks.id", accessibleArgs(user_id)...)
+}
+
+// Condition of tasks which user has at least level on
+func permissionCondition(user_id int32, level domain.Permission) (string, []any) {
+	return "tasks.id IN (SELECT task_id FROM " + accessibleTasks + " AS access WHERE permission >= ?)", append(accessibleArgs(user_id), level)
+}
+
+func getPermission(tx *gorm.DB, id int32, user_id int32) (domain.Permission, error) {
+	var permissions []domain.Permission
+	err := tx.Model(&domain.Task{}).Select(permissionExpr, permissionArgs(user_id)...).Where("id = ?", id).Scan(&permissions).Error
+	if err != nil {
+		return domain.PermissionNone, err
+	}
+	if len(permissions) == 0 {
+		return domain.PermissionNone, domain.ErrTaskNotExists
+	}
+
+	return permissions[0], nil
+}
+
+func requirePermission(tx *gorm.DB, id int32, user_id int32, level domain.Permission) error {
+	permission, err := getPermission(tx, id, user_id)
+	if err != nil {
+		return err
+	}
+	if permission < level {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// Task is shared when reader neither created it nor is assigned to it
+func isShared(task *domain.Task, user_id int32) bool {
+	return task.CreatorId != user_id && (task.AssigneeId == nil || *task.AssigneeId != user_id)
+}
+
+type shareRepository struct {
+	Conn dbservice.Database
+}
+
+func NewShareRepository(conn dbservice.Database) repository.ShareRepository {
+	return &shareRepository{
+		Conn: conn,
+	}
+}
+
+func (s *shareRepository) FetchByTask(ctx context.Context, task_id int32, user_id int32) ([]domain.Share, error) {
+	if err := requirePermission(s.Conn.Db, task_id, user_id, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	var shares []domain.Share
+	if err := s.Conn.Db.Where("task_id = ?", task_id).Order("id asc").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (s *shareRepository) Create(ctx context.Context, user_id int32, info *domain.Share) (*domain.Share, error) {
+	err := s.Conn.Db.Transaction(func(tx *gorm.DB) error {
+		if err := requirePermission(tx, info.TaskId, user_id, domain.PermissionOwner); err != nil {
+			return err
+		}
+
+		// Sharing task with collaborator again changes its permission
+		return tx.Omit("Task").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "task_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"permission"}),
+		}).Create(info).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (s *shareRepository) Delete(ctx context.Context, task_id int32, user_id int32, collaborator_id int32) error {
+	if user_id != collaborator_id {
+		if err := requirePermission(s.Conn.Db, task_id, user_id, domain.PermissionOwner); err != nil {
+			return err
+		}
+	}
+
+	result := s.Conn.Db.Where("task_id = ? AND user_id = ?", task_id, collaborator_id).Delete(&domain.Share{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrShareNotExists
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package postgre
+
+import (
+	"testing"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+func TestPermission(t *testing.T) {
+	_, db := newTestRepository(t, repository.Config{})
+	owner_id := createTestUser(t, db, "owner")
+	viewer_id := createTestUser(t, db, "viewer")
+	editor_id := createTestUser(t, db, "editor")
+	assignee_id := createTestUser(t, db, "assignee")
+	stranger_id := createTestUser(t, db, "stranger")
+
+	root := createTestTask(t, db, domain.Task{Name: "root", CreatorId: owner_id})
+	child := createTestTask(t, db, domain.Task{Name: "child", CreatorId: owner_id, ParentId: &root.ID})
+	grandchild := createTestTask(t, db, domain.Task{Name: "grandchild", CreatorId: owner_id, ParentId: &child.ID, AssigneeId: &assignee_id})
+	shares := []domain.Share{
+		{TaskId: root.ID, UserId: viewer_id, Permission: domain.PermissionViewer},
+		{TaskId: child.ID, UserId: editor_id, Permission: domain.PermissionEditor},
+		{TaskId: grandchild.ID, UserId: editor_id, Permission: domain.PermissionViewer},
+	}
+	if err := db.Omit("Task").Create(&shares).Error; err != nil {
+		t.Fatalf("create shares error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		task_id int32
+		user_id int32
+		want    domain.Permission
+	}{
+		{name: "creator", task_id: root.ID, user_id: owner_id, want: domain.PermissionOwner},
+		{name: "creator of ancestor", task_id: grandchild.ID, user_id: owner_id, want: domain.PermissionOwner},
+		{name: "shared", task_id: root.ID, user_id: viewer_id, want: domain.PermissionViewer},
+		{name: "shared ancestor", task_id: grandchild.ID, user_id: viewer_id, want: domain.PermissionViewer},
+		{name: "share does not go up", task_id: root.ID, user_id: editor_id, want: domain.PermissionNone},
+		{name: "highest of shares", task_id: grandchild.ID, user_id: editor_id, want: domain.PermissionEditor},
+		{name: "assignee", task_id: grandchild.ID, user_id: assignee_id, want: domain.PermissionViewer},
+		{name: "assignment does not go up", task_id: child.ID, user_id: assignee_id, want: domain.PermissionNone},
+		{name: "stranger", task_id: grandchild.ID, user_id: stranger_id, want: domain.PermissionNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			permission, err := getPermission(db, test.task_id, test.user_id)
+			if err != nil {
+				t.Fatalf("getPermission() error: %v", err)
+			}
+			if permission != test.want {
+				t.Fatalf("getPermission() = %v, want %v", permission, test.want)
+			}
+
+			// Set of accessible tasks must agree with permission of single task
+			for _, level := range []domain.Permission{domain.PermissionViewer, domain.PermissionEditor, domain.PermissionOwner} {
+				condition, args := permissionCondition(test.user_id, level)
+				var count int64
+				if err := db.Model(&domain.Task{}).Where("tasks.id = ?", test.task_id).Where(condition, args...).Count(&count).Error; err != nil {
+					t.Fatalf("count accessible tasks error: %v", err)
+				}
+				if got, want := count == 1, test.want >= level; got != want {
+					t.Fatalf("permissionCondition(%v) matches task = %v, want %v", level, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+type templateRepository struct {
+	Conn  dbservice.Database
+	tasks *taskRepository
+}
+
+func NewTemplateRepository(conn dbservice.Database, config repository.Config) repository.TemplateRepository {
+	return &templateRepository{
+		Conn:  conn,
+		tasks: &taskRepository{Conn: conn, Config: config},
+	}
+}
+
+func preloadItems(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Items", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("id asc")
+	}).Preload("Items.Tags")
+}
+
+// Get start of day of time in its location
+func startOfDay(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+}
+
+func (t *templateRepository) Fetch(ctx context.Context, user_id int32) ([]domain.Template, error) {
+	var templates []domain.Template
+	if err := preloadItems(t.Conn.Db).Where("creator_id = ?", user_id).Order("name asc").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Save task and its subtasks as template, due dates are kept as offsets from time task was created
+func (t *templateRepository) Create(ctx context.Context, user_id int32, task_id int32, name string) (*domain.Template, error) {
+	template := &domain.Template{
+		Name:      name,
+		CreatorId: user_id,
+	}
+	err := t.Conn.Db.Transaction(func(tx *gorm.DB) error {
+		if err := requirePermission(tx, task_id, user_id, domain.PermissionViewer); err != nil {
+			return err
+		}
+
+		// Parents come before their children
+		var ids []int32
+		err := tx.Raw(`WITH RECURSIVE descendants AS (
+				SELECT id, 1 AS level FROM tasks WHERE id = ?
+				UNION ALL
+				SELECT tasks.id, descendants.level + 1 FROM tasks JOIN descendants ON tasks.parent_id = descendants.id
+				WHERE tasks.deleted_at IS NULL
+			)
+			SELECT id FROM descendants ORDER BY level, id`, task_id).Scan(&ids).Error
+		if err != nil {
+			return err
+		}
+
+		var tasks []domain.Task
+		if err := tx.Preload("Tags").Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+			return err
+		}
+		tasks_by_id := map[int32]*domain.Task{}
+		for i := range tasks {
+			tasks_by_id[tasks[i].ID] = &tasks[i]
+		}
+		created_at := tasks_by_id[task_id].CreatedAt
+
+		if err := tx.Create(template).Error; err != nil {
+			if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+				if pgError.Code == "23505" {
+					return domain.ErrTemplateExists
+				}
+			}
+			return err
+		}
+
+		item_ids := map[int32]int32{}
+		for _, id := range ids {
+			task := tasks_by_id[id]
+			item := domain.TemplateItem{
+				TemplateId:  template.ID,
+				Name:        task.Name,
+				Description: task.Description,
+				Priority:    task.Priority,
+				DueAllDay:   task.DueAllDay,
+				Tags:        task.Tags,
+			}
+			if id != task_id {
+				parent_id := item_ids[*task.ParentId]
+				item.ParentId = &parent_id
+			}
+			if task.DueAt != nil {
+				// Date only due is kept in whole days from the day task was created
+				base := created_at
+				if task.DueAllDay {
+					location, _ := time.LoadLocation(task.DueTimezone)
+					base = startOfDay(created_at.In(location))
+				}
+				offset := int64(task.DueAt.Sub(base) / time.Second)
+				item.DueOffsetSeconds = &offset
+			}
+
+			if err := tx.Omit("Tags.*").Create(&item).Error; err != nil {
+				return err
+			}
+			item_ids[id] = item.ID
+			template.Items = append(template.Items, item)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (t *templateRepository) Delete(ctx context.Context, id int32, user_id int32) error {
+	result := t.Conn.Db.Where("id = ? AND creator_id = ?", id, user_id).Delete(&domain.Template{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTemplateNotExists
+	}
+
+	return nil
+}
+
+// Create tasks from template, placeholders and due dates follow location of now
+func (t *templateRepository) Instantiate(ctx context.Context, id int32, user_id int32, parent_id *int32, project_id *int32, now time.Time) (*domain.Task, error) {
+	var template domain.Template
+	if err := preloadItems(t.Conn.Db).Where("creator_id = ?", user_id).First(&template, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrTemplateNotExists
+		}
+		return nil, err
+	}
+
+	// Items are saved parents first, so parent level is known before its children
+	levels := map[int32]int32{}
+	var height int32
+	for _, item := range template.Items {
+		levels[item.ID] = 1
+		if item.ParentId != nil {
+			levels[item.ID] = levels[*item.ParentId] + 1
+		}
+		if levels[item.ID] > height {
+			height = levels[item.ID]
+		}
+	}
+
+	var root *domain.Task
+	err := t.tasks.transaction(func(tx *gorm.DB) error {
+		if parent_id != nil {
+			if err := t.tasks.checkParent(tx, 0, *parent_id, height); err != nil {
+				return err
+			}
+			if err := requirePermission(tx, *parent_id, user_id, domain.PermissionEditor); err != nil {
+				return err
+			}
+		}
+		if project_id != nil {
+			if err := requireProject(tx, *project_id, user_id); err != nil {
+				return err
+			}
+		}
+
+		task_ids := map[int32]int32{}
+		for _, item := range template.Items {
+			task := &domain.Task{
+				Name:        domain.ExpandPlaceholders(item.Name, now),
+				Description: domain.ExpandPlaceholders(item.Description, now),
+				CreatorId:   user_id,
+				Priority:    item.Priority,
+				Status:      domain.StatusTodo,
+				ParentId:    parent_id,
+				ProjectId:   project_id,
+				Tags:        item.Tags,
+			}
+			if item.ParentId != nil {
+				task_parent_id := task_ids[*item.ParentId]
+				task.ParentId = &task_parent_id
+			}
+			if item.DueOffsetSeconds != nil {
+				offset := time.Duration(*item.DueOffsetSeconds) * time.Second
+				due := now.Add(offset)
+				if item.DueAllDay {
+					due = startOfDay(startOfDay(now).Add(offset))
+				}
+				task.DueAt = &due
+				task.DueAllDay = item.DueAllDay
+				task.DueTimezone = now.Location().String()
+			}
+
+			rank, err := placeTask(tx, rankList(tx, task.ParentId, task.ProjectId, user_id), 0, 0, 0)
+			if err != nil {
+				return err
+			}
+			task.Rank = rank
+
+			if err := tx.Omit("Tags.*").Create(task).Error; err != nil {
+				return err
+			}
+			if err := recordHistory(tx, task.ID, user_id, domain.HistoryCreated, domain.DiffTasks(nil, task)); err != nil {
+				return err
+			}
+			task_ids[item.ID] = task.ID
+			if root == nil {
+				root = task
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
@@ -0,0 +1,117 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todo-go-grpc/app/dbservice"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Sum of time entries in seconds, running timers count until now
+const trackedSecondsExpr = "COALESCE(SUM(EXTRACT(EPOCH FROM COALESCE(time_entries.ended_at, now()) - time_entries.started_at)), 0)::bigint"
+
+type timeEntryRepository struct {
+	Conn dbservice.Database
+}
+
+func NewTimeEntryRepository(conn dbservice.Database) repository.TimeEntryRepository {
+	return &timeEntryRepository{
+		Conn: conn,
+	}
+}
+
+func (e *timeEntryRepository) Start(ctx context.Context, info *domain.TimeEntry) (*domain.TimeEntry, error) {
+	if err := requirePermission(e.Conn.Db, info.TaskId, info.UserId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	info.EndedAt = nil
+	if err := e.Conn.Db.Omit("Task").Create(info).Error; err != nil {
+		if pgError, ok := err.(*pgconn.PgError); ok && errors.Is(err, pgError) {
+			// Unique index allows one running timer per user
+			if pgError.Code == "23505" {
+				return nil, domain.ErrTimerRunning
+			}
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (e *timeEntryRepository) Stop(ctx context.Context, user_id int32, ended_at time.Time) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	result := e.Conn.Db.Model(&entry).Clauses(clause.Returning{}).
+		Where("user_id = ? AND ended_at IS NULL", user_id).
+		Update("ended_at", ended_at)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrTimerNotRunning
+	}
+
+	return &entry, nil
+}
+
+func (e *timeEntryRepository) Create(ctx context.Context, info *domain.TimeEntry) (*domain.TimeEntry, error) {
+	if err := requirePermission(e.Conn.Db, info.TaskId, info.UserId, domain.PermissionViewer); err != nil {
+		return nil, err
+	}
+
+	if err := e.Conn.Db.Omit("Task").Create(info).Error; err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// Entries are counted in the range when they start in it, days follow location
+func (e *timeEntryRepository) Report(ctx context.Context, user_id int32, from time.Time, to time.Time, location *time.Location) (*domain.TimeReport, error) {
+	report := &domain.TimeReport{}
+	entries := func() *gorm.DB {
+		return e.Conn.Db.Table("time_entries").
+			Where("time_entries.user_id = ? AND time_entries.started_at >= ? AND time_entries.started_at < ?", user_id, from, to)
+	}
+
+	if err := entries().Select(trackedSecondsExpr).Scan(&report.TotalSeconds).Error; err != nil {
+		return nil, err
+	}
+
+	err := entries().
+		Select("(time_entries.started_at AT TIME ZONE ?)::date AS day, "+trackedSecondsExpr+" AS seconds", location.String()).
+		Group("day").Order("day asc").
+		Scan(&report.ByDay).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = entries().
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id").
+		Select("tasks.id AS task_id, tasks.name AS name, " + trackedSecondsExpr + " AS seconds").
+		Group("tasks.id, tasks.name").Order("seconds desc").
+		Scan(&report.ByTask).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Entry of task with several tags is counted in each of them
+	err = entries().
+		Joins("JOIN task_tags ON task_tags.task_id = time_entries.task_id").
+		Joins("JOIN tags ON tags.id = task_tags.tag_id").
+		Select("tags.id AS tag_id, tags.value AS value, " + trackedSecondsExpr + " AS seconds").
+		Group("tags.id, tags.value").Order("seconds desc").
+		Scan(&report.ByTag).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
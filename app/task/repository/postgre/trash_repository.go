@@ -0,0 +1,159 @@
+package postgre
+
+import (
+	"context"
+	"time"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+)
+
+// Subtask moved to trash along with its parent is restored and purged with it, so only parent is listed
+const trashRootCondition = "NOT EXISTS (SELECT 1 FROM tasks AS parents WHERE parents.id = tasks.parent_id AND parents.deleted_at = tasks.deleted_at)"
+
+func (t *taskRepository) FetchTrash(ctx context.Context, user_id int32, offset int32, number int32) ([]domain.Task, error) {
+	var tasks []domain.Task
+	condition, args := permissionCondition(user_id, domain.PermissionOwner)
+	tx := withCounts(t.Conn.Db.Unscoped().Preload("UserCreator").Preload("Tags"), user_id).
+		Where("tasks.deleted_at IS NOT NULL AND "+trashRootCondition).
+		Where(condition, args...)
+
+	if err := tx.Order("deleted_at desc").Order("id asc").Limit(int(number)).Offset(int(offset)).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Check every task is in trash and owned by user
+func checkTrash(tx *gorm.DB, user_id int32, ids []int32) error {
+	var trashed int64
+	if err := tx.Unscoped().Model(&domain.Task{}).Where("id IN ? AND deleted_at IS NOT NULL", ids).Count(&trashed).Error; err != nil {
+		return err
+	}
+	if trashed != int64(len(ids)) {
+		return domain.ErrTaskNotExists
+	}
+
+	condition, args := permissionCondition(user_id, domain.PermissionOwner)
+	var not_owned int64
+	if err := tx.Unscoped().Model(&domain.Task{}).Where("id IN ?", ids).Not(condition, args...).Count(&not_owned).Error; err != nil {
+		return err
+	}
+	if not_owned != 0 {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func (t *taskRepository) Restore(ctx context.Context, user_id int32, ids []int32) error {
+	return t.transaction(func(tx *gorm.DB) error {
+		if err := checkTrash(tx, user_id, ids); err != nil {
+			return err
+		}
+
+		return restoreTasks(tx, user_id, ids)
+	})
+}
+
+// Take tasks out of trash along with subtasks moved to trash with them
+func restoreTasks(tx *gorm.DB, user_id int32, ids []int32) error {
+	var restored_ids []int32
+	err := tx.Raw(`WITH RECURSIVE restored AS (
+			SELECT id, deleted_at FROM tasks WHERE id IN ? AND deleted_at IS NOT NULL
+			UNION ALL
+			SELECT tasks.id, tasks.deleted_at FROM tasks JOIN restored ON tasks.parent_id = restored.id AND tasks.deleted_at = restored.deleted_at
+		)
+		UPDATE tasks SET deleted_at = NULL WHERE id IN (SELECT id FROM restored) RETURNING id`, ids).Scan(&restored_ids).Error
+	if err != nil {
+		return err
+	}
+	if err := recordHistoryOfTasks(tx, restored_ids, user_id, domain.HistoryRestored); err != nil {
+		return err
+	}
+
+	// Task whose parent is still in trash becomes top-level
+	return tx.Exec("UPDATE tasks SET parent_id = NULL WHERE id IN ? AND parent_id IN (SELECT id FROM tasks WHERE deleted_at IS NOT NULL)", ids).Error
+}
+
+// Delete tasks in trash along with their subtasks in trash and get storage keys of their attachments.
+// History of each task is replaced by its tombstone
+func purgeTasks(tx *gorm.DB, user_id int32, ids []int32) ([]string, error) {
+	var purge_ids []int32
+	err := tx.Raw(`WITH RECURSIVE purged AS (
+			SELECT id FROM tasks WHERE id IN ? AND deleted_at IS NOT NULL
+			UNION
+			SELECT tasks.id FROM tasks JOIN purged ON tasks.parent_id = purged.id WHERE tasks.deleted_at IS NOT NULL
+		)
+		SELECT id FROM purged`, ids).Scan(&purge_ids).Error
+	if err != nil || len(purge_ids) == 0 {
+		return nil, err
+	}
+
+	var keys []string
+	if err := tx.Model(&domain.Attachment{}).Where("task_id IN ?", purge_ids).Pluck("storage_key", &keys).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Exec("DELETE FROM task_tags WHERE task_id IN ?", purge_ids).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("task_id IN ?", purge_ids).Delete(&domain.HistoryEntry{}).Error; err != nil {
+		return nil, err
+	}
+	if err := recordHistoryOfTasks(tx, purge_ids, user_id, domain.HistoryPurged); err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Delete(&domain.Task{}, purge_ids).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (t *taskRepository) Purge(ctx context.Context, user_id int32, ids []int32) ([]string, error) {
+	var keys []string
+	err := t.transaction(func(tx *gorm.DB) error {
+		if ids == nil {
+			// Whole trash of user is purged
+			condition, args := permissionCondition(user_id, domain.PermissionOwner)
+			if err := tx.Unscoped().Model(&domain.Task{}).Where("deleted_at IS NOT NULL").Where(condition, args...).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+		} else if err := checkTrash(tx, user_id, ids); err != nil {
+			return err
+		}
+
+		var err error
+		keys, err = purgeTasks(tx, user_id, ids)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Tombstones of expired tasks have no actor, they are purged by service
+func (t *taskRepository) PurgeExpired(ctx context.Context, before time.Time, limit int) ([]string, int, error) {
+	var keys []string
+	var ids []int32
+	err := t.transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&domain.Task{}).Where("deleted_at < ?", before).Order("id asc").Limit(limit).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+
+		keys, err = purgeTasks(tx, 0, ids)
+		return err
+	})
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return keys, len(ids), nil
+}
@@ -0,0 +1,177 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	tagDomain "todo-go-grpc/app/tag/domain"
+	"todo-go-grpc/app/task/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Replace the previous undo operation of user with a new one
+func recordUndo(tx *gorm.DB, undo *domain.UndoOperation) error {
+	if err := tx.Where("user_id = ?", undo.UserId).Delete(&domain.UndoOperation{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(undo).Error
+}
+
+func (t *taskRepository) Undo(ctx context.Context, user_id int32) ([]int32, error) {
+	ids := []int32{}
+	err := t.transaction(func(tx *gorm.DB) error {
+		var undo domain.UndoOperation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND created_at > ?", user_id, time.Now().Add(-t.Config.UndoWindow)).
+			First(&undo).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNothingToUndo
+			}
+			return err
+		}
+
+		for _, undo_task := range undo.Tasks {
+			ids = append(ids, undo_task.TaskId)
+		}
+
+		switch undo.Kind {
+		case domain.UndoDelete:
+			// Only owner of tasks in trash can bring them back
+			if err := checkTrash(tx, user_id, ids); err != nil {
+				if errors.Is(err, domain.ErrTaskNotExists) {
+					return fmt.Errorf("%w: task is restored or purged since", domain.ErrUndoConflict)
+				}
+				return err
+			}
+			// Tasks must still be in trash since the same delete
+			var trashed int64
+			if err := tx.Unscoped().Model(&domain.Task{}).Where("id IN ? AND deleted_at = ?", ids, undo.TrashedAt).Count(&trashed).Error; err != nil {
+				return err
+			}
+			if trashed != int64(len(ids)) {
+				return fmt.Errorf("%w: task is restored or purged since", domain.ErrUndoConflict)
+			}
+			if err := restoreTasks(tx, user_id, ids); err != nil {
+				return err
+			}
+		default:
+			for _, undo_task := range undo.Tasks {
+				if err := t.undoTask(tx, user_id, undo_task); err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Delete(&undo).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Bring task back to its previous state or delete it when operation created it
+func (t *taskRepository) undoTask(tx *gorm.DB, user_id int32, undo_task domain.UndoTask) error {
+	var current domain.Task
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, undo_task.TaskId).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: task %v is deleted since", domain.ErrUndoConflict, undo_task.TaskId)
+		}
+		return err
+	}
+	if current.Version != undo_task.Version {
+		return fmt.Errorf("%w: task %v is modified since", domain.ErrUndoConflict, undo_task.TaskId)
+	}
+	// User may have lost access to task since the operation
+	if err := requirePermission(tx, current.ID, user_id, domain.PermissionEditor); err != nil {
+		return err
+	}
+
+	if undo_task.Created {
+		if err := tx.Exec("DELETE FROM task_tags WHERE task_id = ?", current.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&current).Error; err != nil {
+			return err
+		}
+		// Deleted occurrence is not counted in its series any more
+		if current.SeriesId != nil {
+			return tx.Model(&domain.Series{}).Where("id = ?", *current.SeriesId).Update("occurrences", gorm.Expr("occurrences - 1")).Error
+		}
+		return nil
+	}
+
+	previous := undo_task.Previous
+	if err := t.checkUndoPlace(tx, user_id, &current, previous); err != nil {
+		return err
+	}
+
+	new_task_map := previous.Map()
+	new_task_map["version"] = gorm.Expr("version + 1")
+
+	var task domain.Task
+	if err := tx.Model(&task).Clauses(clause.Returning{}).Where("id = ?", current.ID).Updates(new_task_map).Error; err != nil {
+		return err
+	}
+
+	if previous.TagsId != nil {
+		current.Tags = []tagDomain.Tag{}
+		if err := tx.Model(&current).Association("Tags").Find(&current.Tags); err != nil {
+			return err
+		}
+		task.Tags = []tagDomain.Tag{}
+		for _, tag_id := range previous.TagsId {
+			task.Tags = append(task.Tags, tagDomain.Tag{ID: tag_id})
+		}
+		if err := tx.Model(&task).Association("Tags").Replace(task.Tags); err != nil {
+			return err
+		}
+	}
+
+	if task.Status != current.Status {
+		if err := tx.Omit("Task").Create(&domain.StatusChange{
+			TaskId:     task.ID,
+			FromStatus: current.Status,
+			ToStatus:   task.Status,
+			ChangedBy:  user_id,
+			ChangedAt:  time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	changes := domain.DiffTasks(&current, &task)
+	if task.Rank != current.Rank {
+		changes["rank"] = domain.FieldChange{Before: current.Rank, After: task.Rank}
+	}
+	return recordHistory(tx, task.ID, user_id, domain.HistoryUpdated, changes)
+}
+
+// Task goes back to its previous parent and rank only when it can still be placed there
+func (t *taskRepository) checkUndoPlace(tx *gorm.DB, user_id int32, current *domain.Task, previous *domain.TaskState) error {
+	same_parent := (previous.ParentId == nil) == (current.ParentId == nil) && (previous.ParentId == nil || *previous.ParentId == *current.ParentId)
+	if same_parent && previous.Rank == current.Rank {
+		return nil
+	}
+
+	if !same_parent && previous.ParentId != nil {
+		err := t.checkParent(tx, current.ID, *previous.ParentId, 0)
+		if errors.Is(err, domain.ErrParentNotExists) || errors.Is(err, domain.ErrTaskCycle) || errors.Is(err, domain.ErrTaskTooDeep) {
+			return fmt.Errorf("%w: task %v can not go back to its parent: %v", domain.ErrUndoConflict, current.ID, err)
+		}
+		if err != nil {
+			return err
+		}
+		if err := requirePermission(tx, *previous.ParentId, user_id, domain.PermissionEditor); err != nil {
+			return err
+		}
+	}
+
+	return lock(tx, rankLock, rankList(tx, previous.ParentId, previous.ProjectId, current.CreatorId).key)
+}
@@ -0,0 +1,139 @@
+package postgre
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/repository"
+)
+
+func TestUndo(t *testing.T) {
+	repo, db := newTestRepository(t, repository.Config{})
+	ctx := context.Background()
+	owner_id := createTestUser(t, db, "owner")
+	editor_id := createTestUser(t, db, "editor")
+
+	rename := func(t *testing.T, task_id int32, user_id int32, name string) {
+		var task domain.Task
+		if err := db.First(&task, task_id).Error; err != nil {
+			t.Fatalf("find task error: %v", err)
+		}
+		task.Name = name
+		if _, err := repo.Update(ctx, task_id, user_id, &task, nil, nil, false); err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+	}
+	trash := func(t *testing.T, task_id int32) {
+		if err := repo.Delete(ctx, owner_id, []int32{task_id}, nil); err != nil {
+			t.Fatalf("Delete() error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		// Operation which is undone, then what other users do before the undo
+		operation func(t *testing.T, task_id int32)
+		meantime  func(t *testing.T, task_id int32)
+		want      error
+		// Name of task and whether it is in trash after the undo
+		want_name    string
+		want_trashed bool
+	}{
+		{
+			name:      "update",
+			operation: func(t *testing.T, task_id int32) { rename(t, task_id, owner_id, "renamed") },
+			want_name: "task",
+		},
+		{
+			name:      "update modified since",
+			operation: func(t *testing.T, task_id int32) { rename(t, task_id, owner_id, "renamed") },
+			meantime:  func(t *testing.T, task_id int32) { rename(t, task_id, editor_id, "edited") },
+			want:      domain.ErrUndoConflict,
+			want_name: "edited",
+		},
+		{
+			name:      "update trashed since",
+			operation: func(t *testing.T, task_id int32) { rename(t, task_id, owner_id, "renamed") },
+			meantime: func(t *testing.T, task_id int32) {
+				if err := db.Exec("UPDATE tasks SET deleted_at = now() WHERE id = ?", task_id).Error; err != nil {
+					t.Fatalf("trash task error: %v", err)
+				}
+			},
+			want:         domain.ErrUndoConflict,
+			want_name:    "renamed",
+			want_trashed: true,
+		},
+		{
+			name:      "delete",
+			operation: trash,
+			want_name: "task",
+		},
+		{
+			name:      "delete restored since",
+			operation: trash,
+			meantime: func(t *testing.T, task_id int32) {
+				if err := repo.Restore(ctx, owner_id, []int32{task_id}); err != nil {
+					t.Fatalf("Restore() error: %v", err)
+				}
+			},
+			want:      domain.ErrUndoConflict,
+			want_name: "task",
+		},
+		{
+			name:      "delete trashed again since",
+			operation: trash,
+			meantime: func(t *testing.T, task_id int32) {
+				if err := db.Exec("UPDATE tasks SET deleted_at = deleted_at + interval '1 second' WHERE id = ?", task_id).Error; err != nil {
+					t.Fatalf("trash task error: %v", err)
+				}
+			},
+			want:         domain.ErrUndoConflict,
+			want_name:    "task",
+			want_trashed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := createTestTask(t, db, domain.Task{Name: "task", CreatorId: owner_id})
+			share := domain.Share{TaskId: task.ID, UserId: editor_id, Permission: domain.PermissionEditor}
+			if err := db.Omit("Task").Create(&share).Error; err != nil {
+				t.Fatalf("create share error: %v", err)
+			}
+
+			test.operation(t, task.ID)
+			if test.meantime != nil {
+				test.meantime(t, task.ID)
+			}
+
+			ids, err := repo.Undo(ctx, owner_id)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("Undo() error = %v, want %v", err, test.want)
+			}
+			if err == nil && (len(ids) != 1 || ids[0] != task.ID) {
+				t.Fatalf("Undo() = %v, want [%d]", ids, task.ID)
+			}
+
+			var current domain.Task
+			if err := db.Unscoped().First(&current, task.ID).Error; err != nil {
+				t.Fatalf("find task error: %v", err)
+			}
+			if current.Name != test.want_name || current.DeletedAt.Valid != test.want_trashed {
+				t.Fatalf("task after Undo() is %q trashed %v, want %q trashed %v", current.Name, current.DeletedAt.Valid, test.want_name, test.want_trashed)
+			}
+
+			// Operation is undone once, conflicting one stays until it is replaced or expires
+			want_again := domain.ErrNothingToUndo
+			if test.want != nil {
+				want_again = test.want
+			}
+			if _, err := repo.Undo(ctx, owner_id); !errors.Is(err, want_again) {
+				t.Fatalf("second Undo() error = %v, want %v", err, want_again)
+			}
+			if err := db.Where("user_id = ?", owner_id).Delete(&domain.UndoOperation{}).Error; err != nil {
+				t.Fatalf("clear undo error: %v", err)
+			}
+		})
+	}
+}
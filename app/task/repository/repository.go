@@ -2,15 +2,134 @@ package repository
 
 import (
 	"context"
+	"time"
 	"todo-go-grpc/app/task/domain"
+	"todo-go-grpc/app/task/watch"
 )
 
+type Config struct {
+	// Maximum number of levels in a task hierarchy, top-level task is level 1
+	MaxDepth int32
+	// What happens to open children when their parent is done
+	CompleteParentRule domain.CompleteParentRule
+	// How long the last update or delete of user can be undone
+	UndoWindow time.Duration
+	// Postgres text search configuration used to stem words of tasks and queries, for example english
+	SearchLanguage string
+	// Watchers are woken up after changes of tasks are committed, nil when tasks are not watched
+	Broker watch.Broker
+}
+
 type TaskRepository interface {
 	Fetch(ctx context.Context, user_id int32, offset int32, number int32, conditions map[string]any) ([]domain.Task, error)
-	GetByID(ctx context.Context, id int32) (*domain.Task, error)
+	GetByID(ctx context.Context, id int32, user_id int32) (*domain.Task, error)
 	GetByUserId(ctx context.Context, user_id int32) ([]int32, error)
-	IsExists(ctx context.Context, id int32) (bool, error)
+	IsExists(ctx context.Context, id int32, user_id int32) (bool, error)
+	// Check user has at least level of permission on task
+	CheckPermission(ctx context.Context, id int32, user_id int32, level domain.Permission) error
 	Create(ctx context.Context, user_id int32, info *domain.Task) (*domain.Task, error)
-	Update(ctx context.Context, id int32, new_info *domain.Task, tags_add []int32, tags_remove []int32) (*domain.Task, error)
-	Delete(ctx context.Context, ids []int32) error
+	// Editor can update every field of task, assignee can only update its status.
+	// Task with open blockers can only be done when force is set
+	Update(ctx context.Context, id int32, user_id int32, new_info *domain.Task, tags_add []int32, tags_remove []int32, force bool) (*domain.Task, error)
+	// Create or update every item in one transaction, result of each item is in the order of items.
+	// Atomic batch writes nothing when one of its items fails, otherwise only failed items are left out
+	BatchCreate(ctx context.Context, user_id int32, infos []*domain.Task, atomic bool) ([]domain.BatchResult, error)
+	BatchUpdate(ctx context.Context, user_id int32, updates []domain.TaskUpdate, atomic bool) ([]domain.BatchResult, error)
+	// Write records of tasks created by user one by one, tasks without external id get one which stays with them
+	Export(ctx context.Context, user_id int32, write func(record *domain.TaskRecord) error) error
+	// Create tasks of records, result of each record is in the order of records
+	Import(ctx context.Context, user_id int32, records []domain.TaskRecord, options domain.ImportOptions) ([]domain.ImportResult, error)
+	// Place task under parent and between neighbours before and after it, zero neighbour is not given
+	Move(ctx context.Context, id int32, user_id int32, parent_id *int32, before_id int32, after_id int32, version int32) (*domain.Task, error)
+	// Only owner can delete task, deleted task stays in trash until it is purged
+	Delete(ctx context.Context, user_id int32, ids []int32, versions map[int32]int32) error
+	Skip(ctx context.Context, id int32, user_id int32, version int32) (*domain.Task, error)
+	UpdateSeries(ctx context.Context, id int32, user_id int32, rule string, anchor domain.RecurrenceAnchor) (*domain.Series, error)
+	StopSeries(ctx context.Context, id int32, user_id int32) (*domain.Series, error)
+	// Revert the last update or delete of user within undo window, get ids of tasks it changed
+	Undo(ctx context.Context, user_id int32) ([]int32, error)
+	// Trash is read and changed only by owner of tasks
+	FetchTrash(ctx context.Context, user_id int32, offset int32, number int32) ([]domain.Task, error)
+	Restore(ctx context.Context, user_id int32, ids []int32) error
+	// Delete tasks from trash, whole trash of user when ids is nil, and get storage keys of their attachments
+	Purge(ctx context.Context, user_id int32, ids []int32) ([]string, error)
+	// Delete at most limit tasks moved to trash before given time, number of them is returned along with keys
+	PurgeExpired(ctx context.Context, before time.Time, limit int) ([]string, int, error)
+}
+
+type ReminderRepository interface {
+	FetchByTask(ctx context.Context, task_id int32) ([]domain.Reminder, error)
+	Create(ctx context.Context, info *domain.Reminder) (*domain.Reminder, error)
+	Snooze(ctx context.Context, id int32, user_id int32, until time.Time) (*domain.Reminder, error)
+	Delete(ctx context.Context, id int32, user_id int32) error
+	// Fire reminders which are due at now, reminder is only marked fired when fire succeeds
+	FireDue(ctx context.Context, now time.Time, limit int, fire func(reminder domain.Reminder) error) (int, error)
+}
+
+type CommentRepository interface {
+	FetchByTask(ctx context.Context, task_id int32, offset int32, number int32) ([]domain.Comment, error)
+	Create(ctx context.Context, info *domain.Comment) (*domain.Comment, error)
+	// Only author of comment or owner of task can edit and delete comment
+	Update(ctx context.Context, id int32, user_id int32, body string) (*domain.Comment, error)
+	Delete(ctx context.Context, id int32, user_id int32) error
+}
+
+type AttachmentRepository interface {
+	GetByID(ctx context.Context, id int32) (*domain.Attachment, error)
+	FetchByTask(ctx context.Context, task_id int32) ([]domain.Attachment, error)
+	TotalSizeByUser(ctx context.Context, user_id int32) (int64, error)
+	// Attachment is only created when total size of user stays under max_user_size
+	Create(ctx context.Context, info *domain.Attachment, max_user_size int64) (*domain.Attachment, error)
+	Delete(ctx context.Context, id int32) (*domain.Attachment, error)
+}
+
+type ShareRepository interface {
+	FetchByTask(ctx context.Context, task_id int32, user_id int32) ([]domain.Share, error)
+	// Only owner of task can share it, sharing task with collaborator again changes its permission
+	Create(ctx context.Context, user_id int32, info *domain.Share) (*domain.Share, error)
+	// Owner can unshare task with anyone, collaborator can unshare task with itself to leave it
+	Delete(ctx context.Context, task_id int32, user_id int32, collaborator_id int32) error
+}
+
+type ProjectRepository interface {
+	Fetch(ctx context.Context, user_id int32, include_archived bool) ([]domain.Project, error)
+	GetByID(ctx context.Context, id int32, user_id int32) (*domain.Project, error)
+	Create(ctx context.Context, info *domain.Project) (*domain.Project, error)
+	// Only owner of project can update and delete it
+	Update(ctx context.Context, id int32, user_id int32, new_info *domain.Project) (*domain.Project, error)
+	Delete(ctx context.Context, id int32, user_id int32, version int32) error
+}
+
+type DependencyRepository interface {
+	// Get tasks which block task and tasks which task blocks
+	FetchByTask(ctx context.Context, task_id int32, user_id int32) ([]domain.Task, []domain.Task, error)
+	// Editor of task can make it blocked by any task which user can view
+	Create(ctx context.Context, user_id int32, info *domain.Dependency) (*domain.Dependency, error)
+	Delete(ctx context.Context, task_id int32, blocker_id int32, user_id int32) error
+}
+
+type HistoryRepository interface {
+	FetchByTask(ctx context.Context, task_id int32, user_id int32, offset int32, number int32) ([]domain.HistoryEntry, error)
+	// Get at most limit entries after cursor of tasks user can see, in order of cursor, tasks in trash included.
+	// Entries of transactions which may still be running are left for a later call, true is returned when there are such entries
+	FetchEvents(ctx context.Context, user_id int32, after domain.HistoryCursor, limit int) ([]domain.HistoryEntry, bool, error)
+	// Get cursor before entries of transactions which may still be running
+	LastEventCursor(ctx context.Context) (domain.HistoryCursor, error)
+}
+
+type TimeEntryRepository interface {
+	// User can only have one running timer at a time
+	Start(ctx context.Context, info *domain.TimeEntry) (*domain.TimeEntry, error)
+	Stop(ctx context.Context, user_id int32, ended_at time.Time) (*domain.TimeEntry, error)
+	Create(ctx context.Context, info *domain.TimeEntry) (*domain.TimeEntry, error)
+	Report(ctx context.Context, user_id int32, from time.Time, to time.Time, location *time.Location) (*domain.TimeReport, error)
+}
+
+type TemplateRepository interface {
+	Fetch(ctx context.Context, user_id int32) ([]domain.Template, error)
+	// Save task along with its subtasks as template of user
+	Create(ctx context.Context, user_id int32, task_id int32, name string) (*domain.Template, error)
+	Delete(ctx context.Context, id int32, user_id int32) error
+	// Create tasks from template under parent and in project, both are optional, and get the top-level one
+	Instantiate(ctx context.Context, id int32, user_id int32, parent_id *int32, project_id *int32, now time.Time) (*domain.Task, error)
 }
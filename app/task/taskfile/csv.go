@@ -0,0 +1,169 @@
+package taskfile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo-go-grpc/app/task/domain"
+)
+
+var csvHeader = []string{"external_id", "name", "description", "status", "priority", "due_at", "due_all_day", "due_timezone", "parent_external_id", "tags", "created_at", "done_at"}
+
+const csvTagSeparator = ";"
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339)
+}
+
+type csvWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(w io.Writer) Writer {
+	return &csvWriter{
+		writer: csv.NewWriter(w),
+	}
+}
+
+func (w *csvWriter) Write(record *domain.TaskRecord) error {
+	if !w.wroteHeader {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+
+	return w.writer.Write([]string{
+		record.ExternalId,
+		record.Name,
+		record.Description,
+		record.Status,
+		record.Priority,
+		formatOptionalTime(record.DueAt),
+		strconv.FormatBool(record.DueAllDay),
+		record.DueTimezone,
+		record.ParentExternalId,
+		strings.Join(record.Tags, csvTagSeparator),
+		record.CreatedAt.Format(time.RFC3339),
+		formatOptionalTime(record.DoneAt),
+	})
+}
+
+// File of no task still has its header
+func (w *csvWriter) Close() error {
+	if !w.wroteHeader {
+		if err := w.writer.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	// Index of each known column in header, columns may come in any order and be left out
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) Reader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	return &csvReader{
+		reader: reader,
+	}
+}
+
+func (r *csvReader) Read() (*domain.TaskRecord, error) {
+	if r.columns == nil {
+		header, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		r.columns = map[string]int{}
+		for i, column := range header {
+			r.columns[strings.TrimSpace(column)] = i
+		}
+		if _, ok := r.columns["name"]; !ok {
+			return nil, fmt.Errorf("%w: header has no name column", domain.ErrRecordInvalid)
+		}
+	}
+
+	// Only malformed row is a record error, the reader can not go on after any other error such as closed stream
+	fields, err := r.reader.Read()
+	var parse_err *csv.ParseError
+	if err != nil && !errors.As(err, &parse_err) {
+		return nil, err
+	}
+	r.row++
+	if err != nil {
+		return nil, &RecordError{Row: r.row, Err: fmt.Errorf("%w: %v", domain.ErrRecordInvalid, err)}
+	}
+
+	field := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+	parseTime := func(column string) (*time.Time, error) {
+		value := field(column)
+		if value == "" {
+			return nil, nil
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v %q is not RFC 3339 time", domain.ErrRecordInvalid, column, value)
+		}
+		return &parsed, nil
+	}
+
+	record := &domain.TaskRecord{
+		ExternalId:       field("external_id"),
+		Name:             field("name"),
+		Description:      field("description"),
+		Status:           field("status"),
+		Priority:         field("priority"),
+		DueTimezone:      field("due_timezone"),
+		ParentExternalId: field("parent_external_id"),
+		Tags:             []string{},
+	}
+	for _, tag := range strings.Split(field("tags"), csvTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			record.Tags = append(record.Tags, tag)
+		}
+	}
+	if value := field("due_all_day"); value != "" {
+		if record.DueAllDay, err = strconv.ParseBool(value); err != nil {
+			return nil, &RecordError{Row: r.row, Err: fmt.Errorf("%w: due_all_day %q is not a boolean", domain.ErrRecordInvalid, value)}
+		}
+	}
+	if record.DueAt, err = parseTime("due_at"); err != nil {
+		return nil, &RecordError{Row: r.row, Err: err}
+	}
+	if record.DoneAt, err = parseTime("done_at"); err != nil {
+		return nil, &RecordError{Row: r.row, Err: err}
+	}
+	created_at, err := parseTime("created_at")
+	if err != nil {
+		return nil, &RecordError{Row: r.row, Err: err}
+	}
+	if created_at != nil {
+		record.CreatedAt = *created_at
+	}
+
+	return record, nil
+}
+
+func (r *csvReader) Row() int {
+	return r.row
+}
@@ -0,0 +1,442 @@
+package taskfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo-go-grpc/app/task/domain"
+)
+
+// RFC 5545 lines are at most 75 octets long without line break, longer ones are folded
+const icalendarLineLength = 75
+
+const (
+	icalendarDateTime    = "20060102T150405"
+	icalendarDateTimeUTC = "20060102T150405Z"
+	icalendarDate        = "20060102"
+)
+
+// VTODO has no blocked status, and timezone of due is kept out of TZID which would need a VTIMEZONE component,
+// so both are kept in extension properties
+const (
+	icalendarStatusProperty   = "X-TASK-STATUS"
+	icalendarTimezoneProperty = "X-DUE-TIMEZONE"
+)
+
+var icalendarStatuses = map[string]string{
+	"TODO":        "NEEDS-ACTION",
+	"IN_PROGRESS": "IN-PROCESS",
+	"BLOCKED":     "NEEDS-ACTION",
+	"DONE":        "COMPLETED",
+	"CANCELLED":   "CANCELLED",
+}
+
+var icalendarPriorities = map[string]int{
+	"URGENT": 1,
+	"HIGH":   3,
+	"MEDIUM": 5,
+	"LOW":    7,
+}
+
+// Priority 1 is the highest and 9 the lowest, 0 is no priority
+func parseIcalendarPriority(value int) string {
+	switch {
+	case value == 1:
+		return "URGENT"
+	case value >= 2 && value <= 4:
+		return "HIGH"
+	case value == 5:
+		return "MEDIUM"
+	case value >= 6 && value <= 9:
+		return "LOW"
+	}
+	return "NONE"
+}
+
+var icalendarEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeIcalendarText(text string) string {
+	return icalendarEscaper.Replace(text)
+}
+
+// Split text at unescaped separator and unescape every part
+func unescapeIcalendarText(text string, separator rune) []string {
+	parts := []string{}
+	var part strings.Builder
+	escaped := false
+	for _, char := range text {
+		switch {
+		case escaped:
+			if char == 'n' || char == 'N' {
+				part.WriteRune('\n')
+			} else {
+				part.WriteRune(char)
+			}
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == separator:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(char)
+		}
+	}
+	return append(parts, part.String())
+}
+
+func unescapeIcalendarValue(text string) string {
+	return unescapeIcalendarText(text, -1)[0]
+}
+
+type icalendarWriter struct {
+	writer      *bufio.Writer
+	wroteHeader bool
+	// Time file is created at, DTSTAMP of every VTODO
+	stamp time.Time
+}
+
+func newIcalendarWriter(w io.Writer) Writer {
+	return &icalendarWriter{
+		writer: bufio.NewWriter(w),
+		stamp:  time.Now(),
+	}
+}
+
+// Fold line after every 75 octets without splitting a character, continuation starts with a space
+func (w *icalendarWriter) line(content string) {
+	for length := icalendarLineLength; len(content) > length; length = icalendarLineLength - 1 {
+		cut := length
+		for cut > 0 && content[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.writer.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+	}
+	w.writer.WriteString(content + "\r\n")
+}
+
+func (w *icalendarWriter) header() {
+	if !w.wroteHeader {
+		w.line("BEGIN:VCALENDAR")
+		w.line("VERSION:2.0")
+		w.line("PRODID:-//todo-go-grpc//Tasks//EN")
+		w.wroteHeader = true
+	}
+}
+
+// Every time is written in UTC except all-day due which is a date
+func (w *icalendarWriter) Write(record *domain.TaskRecord) error {
+	w.header()
+
+	w.line("BEGIN:VTODO")
+	w.line("UID:" + escapeIcalendarText(record.ExternalId))
+	w.line("DTSTAMP:" + w.stamp.UTC().Format(icalendarDateTimeUTC))
+	w.line("CREATED:" + record.CreatedAt.UTC().Format(icalendarDateTimeUTC))
+	w.line("SUMMARY:" + escapeIcalendarText(record.Name))
+	if record.Description != "" {
+		w.line("DESCRIPTION:" + escapeIcalendarText(record.Description))
+	}
+	if status, ok := icalendarStatuses[record.Status]; ok {
+		w.line("STATUS:" + status)
+	}
+	if record.Status == "BLOCKED" {
+		w.line(icalendarStatusProperty + ":" + record.Status)
+	}
+	if record.DoneAt != nil {
+		w.line("COMPLETED:" + record.DoneAt.UTC().Format(icalendarDateTimeUTC))
+	}
+	if record.DueAt != nil {
+		location, err := time.LoadLocation(record.DueTimezone)
+		if err != nil {
+			location = time.UTC
+		}
+		if record.DueAllDay {
+			w.line("DUE;VALUE=DATE:" + record.DueAt.In(location).Format(icalendarDate))
+		} else {
+			w.line("DUE:" + record.DueAt.UTC().Format(icalendarDateTimeUTC))
+		}
+		if location != time.UTC {
+			w.line(icalendarTimezoneProperty + ":" + location.String())
+		}
+	}
+	if priority, ok := icalendarPriorities[record.Priority]; ok {
+		w.line("PRIORITY:" + strconv.Itoa(priority))
+	}
+	if len(record.Tags) != 0 {
+		categories := []string{}
+		for _, tag := range record.Tags {
+			categories = append(categories, escapeIcalendarText(tag))
+		}
+		w.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if record.ParentExternalId != "" {
+		w.line("RELATED-TO;RELTYPE=PARENT:" + escapeIcalendarText(record.ParentExternalId))
+	}
+	w.line("END:VTODO")
+
+	return nil
+}
+
+func (w *icalendarWriter) Close() error {
+	w.header()
+	w.line("END:VCALENDAR")
+	return w.writer.Flush()
+}
+
+// Property of component with its parameters, names are upper case
+type icalendarProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+func parseIcalendarProperty(line string) (icalendarProperty, error) {
+	property := icalendarProperty{params: map[string]string{}}
+
+	// Value starts at the first colon out of quoted parameter values
+	quoted := false
+	colon := -1
+	for i, char := range line {
+		if char == '"' {
+			quoted = !quoted
+		}
+		if char == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property, fmt.Errorf("%w: line %q has no value", domain.ErrRecordInvalid, line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	property.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		property.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	property.value = line[colon+1:]
+	return property, nil
+}
+
+// Date-time in UTC, in timezone of TZID or floating one which is read as UTC, date is midnight of the day
+func parseIcalendarTime(property icalendarProperty) (time.Time, bool, error) {
+	location := time.UTC
+	if tzid, ok := property.params["TZID"]; ok {
+		var err error
+		if location, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: timezone %q of %v is not valid", domain.ErrRecordInvalid, tzid, property.name)
+		}
+	}
+
+	if property.params["VALUE"] == "DATE" || len(property.value) == len(icalendarDate) {
+		value, err := time.ParseInLocation(icalendarDate, property.value, location)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %v %q is not a date", domain.ErrRecordInvalid, property.name, property.value)
+		}
+		return value, true, nil
+	}
+	if strings.HasSuffix(property.value, "Z") {
+		value, err := time.Parse(icalendarDateTimeUTC, property.value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: %v %q is not a date-time", domain.ErrRecordInvalid, property.name, property.value)
+		}
+		return value, false, nil
+	}
+	value, err := time.ParseInLocation(icalendarDateTime, property.value, location)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: %v %q is not a date-time", domain.ErrRecordInvalid, property.name, property.value)
+	}
+	return value, false, nil
+}
+
+type icalendarReader struct {
+	scanner *bufio.Scanner
+	// Line read ahead to find out whether it is folded into the previous one
+	next    string
+	hasNext bool
+	row     int
+}
+
+func newIcalendarReader(r io.Reader) Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &icalendarReader{
+		scanner: scanner,
+	}
+}
+
+// Get the next unfolded line, false at the end of file
+func (r *icalendarReader) line() (string, bool, error) {
+	if !r.hasNext {
+		if !r.scanner.Scan() {
+			return "", false, r.scanner.Err()
+		}
+		r.next = strings.TrimRight(r.scanner.Text(), "\r")
+	}
+
+	line := r.next
+	r.hasNext = false
+	for r.scanner.Scan() {
+		next := strings.TrimRight(r.scanner.Text(), "\r")
+		if strings.HasPrefix(next, " ") || strings.HasPrefix(next, "\t") {
+			line += next[1:]
+			continue
+		}
+		r.next, r.hasNext = next, true
+		break
+	}
+	return line, true, r.scanner.Err()
+}
+
+// Components other than VTODO are skipped, so are components nested in VTODO such as VALARM
+func (r *icalendarReader) Read() (*domain.TaskRecord, error) {
+	for {
+		line, ok, err := r.line()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, io.EOF
+		}
+		if strings.EqualFold(line, "BEGIN:VTODO") {
+			r.row++
+			record, err := r.todo()
+			if err != nil {
+				return nil, &RecordError{Row: r.row, Err: err}
+			}
+			return record, nil
+		}
+	}
+}
+
+// Read properties of VTODO until its end, the whole component is read even when one of them is not valid
+func (r *icalendarReader) todo() (*domain.TaskRecord, error) {
+	record := &domain.TaskRecord{Tags: []string{}}
+	var record_err error
+	fail := func(err error) {
+		if record_err == nil {
+			record_err = err
+		}
+	}
+
+	status := ""
+	timezone := ""
+	depth := 0
+	for {
+		line, ok, err := r.line()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: VTODO has no end", domain.ErrRecordInvalid)
+		}
+
+		property, err := parseIcalendarProperty(line)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		if property.name == "BEGIN" {
+			depth++
+			continue
+		}
+		if property.name == "END" {
+			if depth == 0 {
+				break
+			}
+			depth--
+			continue
+		}
+		if depth != 0 {
+			continue
+		}
+
+		switch property.name {
+		case "UID":
+			record.ExternalId = unescapeIcalendarValue(property.value)
+		case "SUMMARY":
+			record.Name = unescapeIcalendarValue(property.value)
+		case "DESCRIPTION":
+			record.Description = unescapeIcalendarValue(property.value)
+		case "STATUS":
+			for name, value := range icalendarStatuses {
+				if strings.EqualFold(value, property.value) && name != "BLOCKED" {
+					record.Status = name
+				}
+			}
+		case icalendarStatusProperty:
+			status = property.value
+		case "COMPLETED":
+			done_at, _, err := parseIcalendarTime(property)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			record.DoneAt = &done_at
+		case "CREATED":
+			created_at, _, err := parseIcalendarTime(property)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			record.CreatedAt = created_at
+		case "DUE":
+			due_at, all_day, err := parseIcalendarTime(property)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			record.DueAt = &due_at
+			record.DueAllDay = all_day
+			record.DueTimezone = due_at.Location().String()
+		case icalendarTimezoneProperty:
+			timezone = property.value
+		case "PRIORITY":
+			priority, err := strconv.Atoi(property.value)
+			if err != nil {
+				fail(fmt.Errorf("%w: priority %q is not a number", domain.ErrRecordInvalid, property.value))
+				continue
+			}
+			record.Priority = parseIcalendarPriority(priority)
+		case "CATEGORIES":
+			for _, category := range unescapeIcalendarText(property.value, ',') {
+				if category = strings.TrimSpace(category); category != "" {
+					record.Tags = append(record.Tags, category)
+				}
+			}
+		case "RELATED-TO":
+			if reltype, ok := property.params["RELTYPE"]; !ok || strings.EqualFold(reltype, "PARENT") {
+				record.ParentExternalId = unescapeIcalendarValue(property.value)
+			}
+		}
+	}
+
+	if status != "" {
+		record.Status = status
+	}
+	// All-day due is midnight of its day in its own timezone, due with time is the same instant in it
+	if timezone != "" && record.DueAt != nil {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			fail(fmt.Errorf("%w: timezone %q is not valid", domain.ErrRecordInvalid, timezone))
+		} else {
+			due_at := record.DueAt.In(location)
+			if record.DueAllDay {
+				due_at = time.Date(record.DueAt.Year(), record.DueAt.Month(), record.DueAt.Day(), 0, 0, 0, 0, location)
+			}
+			record.DueAt = &due_at
+			record.DueTimezone = location.String()
+		}
+	}
+
+	return record, record_err
+}
+
+func (r *icalendarReader) Row() int {
+	return r.row
+}
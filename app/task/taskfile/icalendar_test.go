@@ -0,0 +1,380 @@
+package taskfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"todo-go-grpc/app/task/domain"
+	"unicode/utf8"
+)
+
+func writeIcalendar(t *testing.T, records []domain.TaskRecord) string {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := newIcalendarWriter(&buffer)
+	for i := range records {
+		if err := writer.Write(&records[i]); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	return buffer.String()
+}
+
+// Get unfolded properties with the given name
+func icalendarLines(data string, name string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n ", ""), "\r\n") {
+		if strings.HasPrefix(line, name+":") || strings.HasPrefix(line, name+";") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestEscapeIcalendarText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "plain", want: "plain"},
+		{text: "a,b;c", want: `a\,b\;c`},
+		{text: `back\slash`, want: `back\\slash`},
+		{text: "two\nlines", want: `two\nlines`},
+		{text: "windows\r\nline", want: `windows\nline`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			escaped := escapeIcalendarText(test.text)
+			if escaped != test.want {
+				t.Fatalf("escapeIcalendarText(%q) = %q, want %q", test.text, escaped, test.want)
+			}
+			want := strings.ReplaceAll(test.text, "\r\n", "\n")
+			if got := unescapeIcalendarValue(escaped); got != want {
+				t.Fatalf("unescapeIcalendarValue(%q) = %q, want %q", escaped, got, want)
+			}
+		})
+	}
+}
+
+func TestUnescapeIcalendarList(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "one", want: []string{"one"}},
+		{text: "one,two", want: []string{"one", "two"}},
+		{text: `a\,b,c`, want: []string{"a,b", "c"}},
+		{text: `a\\,b`, want: []string{`a\`, "b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if got := unescapeIcalendarText(test.text, ','); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("unescapeIcalendarText(%q) = %q, want %q", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+// Long line is folded at 75 octets without splitting a character and is unfolded back
+func TestIcalendarFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "ascii", text: strings.Repeat("abcdefghij", 20)},
+		{name: "two byte characters", text: strings.Repeat("äöü", 60)},
+		{name: "three byte characters", text: "x" + strings.Repeat("日本語", 40)},
+		{name: "escaped", text: strings.Repeat("a,b;c\n", 30)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			record := domain.TaskRecord{ExternalId: "uid", Name: test.text, Tags: []string{}}
+			data := writeIcalendar(t, []domain.TaskRecord{record})
+
+			for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+				if len(line) > icalendarLineLength {
+					t.Fatalf("line %q is %d octets long", line, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Fatalf("line %q splits a character", line)
+				}
+			}
+
+			reader := newIcalendarReader(strings.NewReader(data))
+			read, err := reader.Read()
+			if err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+			if read.Name != test.text {
+				t.Fatalf("read name %q, want %q", read.Name, test.text)
+			}
+		})
+	}
+}
+
+func TestIcalendarRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+	due := time.Date(2026, time.March, 1, 9, 30, 0, 0, berlin)
+	all_day := time.Date(2026, time.March, 2, 0, 0, 0, 0, berlin)
+	utc_due := time.Date(2026, time.March, 3, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2026, time.February, 1, 12, 0, 0, 0, time.UTC)
+	done := time.Date(2026, time.February, 2, 8, 15, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		record domain.TaskRecord
+	}{
+		{
+			name: "every field",
+			record: domain.TaskRecord{
+				ExternalId:       "a;b,c@example.com",
+				Name:             "Plan, trip; soon",
+				Description:      "first\nsecond",
+				Status:           "DONE",
+				Priority:         "HIGH",
+				DueAt:            &due,
+				DueTimezone:      "Europe/Berlin",
+				ParentExternalId: "parent",
+				Tags:             []string{"travel", "a,b"},
+				CreatedAt:        created,
+				DoneAt:           &done,
+			},
+		},
+		{
+			name: "blocked all day",
+			record: domain.TaskRecord{
+				ExternalId:  "blocked",
+				Name:        "Wait",
+				Status:      "BLOCKED",
+				Priority:    "URGENT",
+				DueAt:       &all_day,
+				DueAllDay:   true,
+				DueTimezone: "Europe/Berlin",
+				Tags:        []string{},
+				CreatedAt:   created,
+			},
+		},
+		{
+			name: "due in UTC",
+			record: domain.TaskRecord{
+				ExternalId:  "utc",
+				Name:        "Call",
+				Status:      "IN_PROGRESS",
+				Priority:    "LOW",
+				DueAt:       &utc_due,
+				DueTimezone: "UTC",
+				Tags:        []string{},
+				CreatedAt:   created,
+			},
+		},
+		{
+			name: "no due",
+			record: domain.TaskRecord{
+				ExternalId: "plain",
+				Name:       "Read",
+				Status:     "TODO",
+				// No priority is not written, it is read back empty
+				Priority:  "",
+				Tags:      []string{},
+				CreatedAt: created,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := writeIcalendar(t, []domain.TaskRecord{test.record})
+			reader := newIcalendarReader(strings.NewReader(data))
+			read, err := reader.Read()
+			if err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+
+			want := test.record
+			if (read.DueAt == nil) != (want.DueAt == nil) || (want.DueAt != nil && !read.DueAt.Equal(*want.DueAt)) {
+				t.Fatalf("read due %v, want %v", read.DueAt, want.DueAt)
+			}
+			if want.DueAt != nil && read.DueAt.Location().String() != want.DueTimezone {
+				t.Fatalf("read due in %v, want %v", read.DueAt.Location(), want.DueTimezone)
+			}
+			if (read.DoneAt == nil) != (want.DoneAt == nil) || (want.DoneAt != nil && !read.DoneAt.Equal(*want.DoneAt)) {
+				t.Fatalf("read done %v, want %v", read.DoneAt, want.DoneAt)
+			}
+			if !read.CreatedAt.Equal(want.CreatedAt) {
+				t.Fatalf("read created %v, want %v", read.CreatedAt, want.CreatedAt)
+			}
+
+			read.DueAt, want.DueAt = nil, nil
+			read.DoneAt, want.DoneAt = nil, nil
+			read.CreatedAt, want.CreatedAt = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(*read, want) {
+				t.Fatalf("read record %+v, want %+v", *read, want)
+			}
+			if _, err := reader.Read(); err != io.EOF {
+				t.Fatalf("last Read() error = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+// Timed due is written in UTC with its timezone in extension property, never with TZID
+func TestIcalendarDue(t *testing.T) {
+	new_york, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+	due := time.Date(2026, time.March, 1, 9, 0, 0, 0, new_york)
+	late := time.Date(2026, time.March, 1, 22, 0, 0, 0, new_york)
+
+	tests := []struct {
+		name     string
+		record   domain.TaskRecord
+		due      string
+		timezone []string
+	}{
+		{
+			name:     "timed in timezone",
+			record:   domain.TaskRecord{DueAt: &due, DueTimezone: "America/New_York"},
+			due:      "DUE:20260301T140000Z",
+			timezone: []string{"X-DUE-TIMEZONE:America/New_York"},
+		},
+		{
+			name:     "timed in UTC",
+			record:   domain.TaskRecord{DueAt: &due},
+			due:      "DUE:20260301T140000Z",
+			timezone: []string{},
+		},
+		{
+			name:     "all day is date in its timezone",
+			record:   domain.TaskRecord{DueAt: &late, DueAllDay: true, DueTimezone: "America/New_York"},
+			due:      "DUE;VALUE=DATE:20260301",
+			timezone: []string{"X-DUE-TIMEZONE:America/New_York"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := writeIcalendar(t, []domain.TaskRecord{test.record})
+			if strings.Contains(data, "TZID") {
+				t.Fatalf("file has TZID:\n%v", data)
+			}
+			if got := icalendarLines(data, "DUE"); !reflect.DeepEqual(got, []string{test.due}) {
+				t.Fatalf("due lines %q, want %q", got, test.due)
+			}
+			if got := icalendarLines(data, icalendarTimezoneProperty); !reflect.DeepEqual(got, test.timezone) {
+				t.Fatalf("timezone lines %q, want %q", got, test.timezone)
+			}
+		})
+	}
+}
+
+// Every VTODO of file is stamped with the time file is written, not with time task is created
+func TestIcalendarStamp(t *testing.T) {
+	created := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	records := []domain.TaskRecord{{ExternalId: "a", CreatedAt: created}, {ExternalId: "b", CreatedAt: created}}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	data := writeIcalendar(t, records)
+	after := time.Now().UTC()
+
+	stamps := icalendarLines(data, "DTSTAMP")
+	if len(stamps) != len(records) {
+		t.Fatalf("got %d DTSTAMP lines, want %d", len(stamps), len(records))
+	}
+	for _, stamp := range stamps {
+		if stamp != stamps[0] {
+			t.Fatalf("stamps differ: %q and %q", stamp, stamps[0])
+		}
+		value, err := time.Parse(icalendarDateTimeUTC, strings.TrimPrefix(stamp, "DTSTAMP:"))
+		if err != nil {
+			t.Fatalf("stamp %q is not UTC date-time: %v", stamp, err)
+		}
+		if value.Before(before) || value.After(after) {
+			t.Fatalf("stamp %v is not between %v and %v", value, before, after)
+		}
+	}
+}
+
+func TestIcalendarRead(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want domain.TaskRecord
+	}{
+		{
+			name: "TZID of due",
+			data: "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:1\r\nSUMMARY:a\r\nDUE;TZID=Europe/Berlin:20260301T093000\r\nEND:VTODO\r\nEND:VCALENDAR\r\n",
+			want: domain.TaskRecord{ExternalId: "1", Name: "a", DueTimezone: "Europe/Berlin", Tags: []string{}},
+		},
+		{
+			name: "alarm and other components are skipped",
+			data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:event\nEND:VEVENT\nBEGIN:VTODO\nUID:2\nSUMMARY:todo\nBEGIN:VALARM\nSUMMARY:alarm\nEND:VALARM\nEND:VTODO\nEND:VCALENDAR\n",
+			want: domain.TaskRecord{ExternalId: "2", Name: "todo", Tags: []string{}},
+		},
+		{
+			name: "folded with tab",
+			data: "BEGIN:VTODO\r\nUID:3\r\nSUMMARY:long\r\n\t name\r\nPRIORITY:2\r\nEND:VTODO\r\n",
+			want: domain.TaskRecord{ExternalId: "3", Name: "long name", Priority: "HIGH", Tags: []string{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			read, err := newIcalendarReader(strings.NewReader(test.data)).Read()
+			if err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+			if read.DueAt != nil {
+				want_due := time.Date(2026, time.March, 1, 9, 30, 0, 0, berlin)
+				if !read.DueAt.Equal(want_due) {
+					t.Fatalf("read due %v, want %v", read.DueAt, want_due)
+				}
+				read.DueAt = nil
+			}
+			if !reflect.DeepEqual(*read, test.want) {
+				t.Fatalf("read record %+v, want %+v", *read, test.want)
+			}
+		})
+	}
+}
+
+// Invalid VTODO fails alone, the next one is still read
+func TestIcalendarReadRecordError(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VTODO\r\nUID:bad\r\nPRIORITY:high\r\nEND:VTODO\r\n" +
+		"BEGIN:VTODO\r\nUID:good\r\nEND:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+	reader := newIcalendarReader(strings.NewReader(data))
+
+	_, err := reader.Read()
+	var record_err *RecordError
+	if !errors.As(err, &record_err) || !errors.Is(err, domain.ErrRecordInvalid) || record_err.Row != 1 {
+		t.Fatalf("first Read() error = %v, want invalid record 1", err)
+	}
+
+	record, err := reader.Read()
+	if err != nil {
+		t.Fatalf("second Read() error: %v", err)
+	}
+	if record.ExternalId != "good" || reader.Row() != 2 {
+		t.Fatalf("second Read() = %q at row %d, want good at row 2", record.ExternalId, reader.Row())
+	}
+}
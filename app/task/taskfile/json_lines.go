@@ -0,0 +1,71 @@
+package taskfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"todo-go-grpc/app/task/domain"
+)
+
+type jsonLinesWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(w io.Writer) Writer {
+	writer := bufio.NewWriter(w)
+	return &jsonLinesWriter{
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// Encoder ends every record with a new line
+func (w *jsonLinesWriter) Write(record *domain.TaskRecord) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *jsonLinesWriter) Close() error {
+	return w.writer.Flush()
+}
+
+type jsonLinesReader struct {
+	reader *bufio.Reader
+	row    int
+}
+
+func newJSONLinesReader(r io.Reader) Reader {
+	return &jsonLinesReader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Empty lines are not records
+func (r *jsonLinesReader) Read() (*domain.TaskRecord, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		r.row++
+		var record domain.TaskRecord
+		if decode_err := json.Unmarshal(line, &record); decode_err != nil {
+			return nil, &RecordError{Row: r.row, Err: fmt.Errorf("%w: %v", domain.ErrRecordInvalid, decode_err)}
+		}
+		return &record, nil
+	}
+}
+
+func (r *jsonLinesReader) Row() int {
+	return r.row
+}
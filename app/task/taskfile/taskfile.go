@@ -0,0 +1,71 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"todo-go-grpc/app/task/domain"
+)
+
+type Format string
+
+const (
+	// One JSON object per line
+	FormatJSONLines Format = "JSON_LINES"
+	// Header row followed by one row per task, tags are separated by semicolon
+	FormatCSV Format = "CSV"
+	// RFC 5545 VCALENDAR of VTODO components, external id of task is UID and its tags are CATEGORIES
+	FormatIcalendar Format = "ICALENDAR"
+)
+
+var ErrFormatNotSupported = errors.New("ErrFormatNotSupported")
+
+// Writer encodes records one by one, Close writes what is left and must be called at the end
+type Writer interface {
+	Write(record *domain.TaskRecord) error
+	Close() error
+}
+
+// Reader decodes records one by one and returns io.EOF at the end. Invalid record is returned
+// as RecordError, reading can go on after it. Row is the number of the last record read
+type Reader interface {
+	Read() (*domain.TaskRecord, error)
+	Row() int
+}
+
+type RecordError struct {
+	Row int
+	Err error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Row, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSONLines:
+		return newJSONLinesWriter(w), nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatIcalendar:
+		return newIcalendarWriter(w), nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrFormatNotSupported, format)
+}
+
+func NewReader(format Format, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatJSONLines:
+		return newJSONLinesReader(r), nil
+	case FormatCSV:
+		return newCSVReader(r), nil
+	case FormatIcalendar:
+		return newIcalendarReader(r), nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrFormatNotSupported, format)
+}
@@ -0,0 +1,217 @@
+package taskfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"todo-go-grpc/app/task/domain"
+)
+
+// Reader of stream which breaks after data, as a cancelled upload does
+type failingReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func testRecords() []domain.TaskRecord {
+	due := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	done := time.Date(2026, time.March, 2, 18, 30, 0, 0, time.UTC)
+	return []domain.TaskRecord{
+		{
+			ExternalId:  "parent",
+			Name:        "Plan, \"trip\"",
+			Description: "first line\nsecond line",
+			Status:      "DONE",
+			Priority:    "HIGH",
+			DueAt:       &due,
+			DueAllDay:   false,
+			Tags:        []string{"travel", "family"},
+			CreatedAt:   time.Date(2026, time.February, 1, 12, 0, 0, 0, time.UTC),
+			DoneAt:      &done,
+		},
+		{
+			ExternalId:       "child",
+			Name:             "Book hotel",
+			Status:           "TODO",
+			Priority:         "NONE",
+			DueAt:            &due,
+			DueAllDay:        true,
+			DueTimezone:      "Europe/Berlin",
+			ParentExternalId: "parent",
+			Tags:             []string{},
+			CreatedAt:        time.Date(2026, time.February, 1, 12, 5, 0, 0, time.UTC),
+		},
+	}
+}
+
+func readAll(t *testing.T, reader Reader) []domain.TaskRecord {
+	t.Helper()
+	records := []domain.TaskRecord{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		records = append(records, *record)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		records []domain.TaskRecord
+	}{
+		{name: "json lines", format: FormatJSONLines, records: testRecords()},
+		{name: "csv", format: FormatCSV, records: testRecords()},
+		{name: "empty json lines", format: FormatJSONLines, records: []domain.TaskRecord{}},
+		{name: "empty csv", format: FormatCSV, records: []domain.TaskRecord{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			writer, err := NewWriter(test.format, &buffer)
+			if err != nil {
+				t.Fatalf("NewWriter() error: %v", err)
+			}
+			for i := range test.records {
+				if err := writer.Write(&test.records[i]); err != nil {
+					t.Fatalf("Write() error: %v", err)
+				}
+			}
+			if err := writer.Close(); err != nil {
+				t.Fatalf("Close() error: %v", err)
+			}
+
+			reader, err := NewReader(test.format, &buffer)
+			if err != nil {
+				t.Fatalf("NewReader() error: %v", err)
+			}
+			if got := readAll(t, reader); !reflect.DeepEqual(got, test.records) {
+				t.Fatalf("read records %+v, want %+v", got, test.records)
+			}
+			if reader.Row() != len(test.records) {
+				t.Fatalf("Row() = %d, want %d", reader.Row(), len(test.records))
+			}
+		})
+	}
+}
+
+// Invalid record fails alone, records after it are still read
+func TestReadRecordError(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{name: "json lines syntax", format: FormatJSONLines, data: "{\"name\":\n{\"name\":\"valid\"}\n"},
+		{name: "json lines type", format: FormatJSONLines, data: "{\"name\":1}\n\n{\"name\":\"valid\"}\n"},
+		{name: "csv bare quote", format: FormatCSV, data: "name,status\nbad \"quote,TODO\nvalid,TODO\n"},
+		{name: "csv time", format: FormatCSV, data: "name,due_at\nbad,tomorrow\nvalid,\n"},
+		{name: "csv boolean", format: FormatCSV, data: "name,due_all_day\nbad,maybe\nvalid,true\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, err := NewReader(test.format, strings.NewReader(test.data))
+			if err != nil {
+				t.Fatalf("NewReader() error: %v", err)
+			}
+
+			_, err = reader.Read()
+			var record_err *RecordError
+			if !errors.As(err, &record_err) || !errors.Is(err, domain.ErrRecordInvalid) {
+				t.Fatalf("first Read() error = %v, want invalid record", err)
+			}
+			if record_err.Row != 1 {
+				t.Fatalf("record error row = %d, want 1", record_err.Row)
+			}
+
+			record, err := reader.Read()
+			if err != nil {
+				t.Fatalf("second Read() error: %v", err)
+			}
+			if record.Name != "valid" || reader.Row() != 2 {
+				t.Fatalf("second Read() = %q at row %d, want valid at row 2", record.Name, reader.Row())
+			}
+			if _, err := reader.Read(); err != io.EOF {
+				t.Fatalf("last Read() error = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+// Broken stream is not a record error, import must stop on it
+func TestReadStreamError(t *testing.T) {
+	stream_err := errors.New("stream reset")
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{name: "json lines", format: FormatJSONLines, data: "{\"name\":\"a\"}\n{\"name\":"},
+		{name: "csv header", format: FormatCSV, data: "name,sta"},
+		{name: "csv row", format: FormatCSV, data: "name,status\na,TODO\nb,TO"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, err := NewReader(test.format, &failingReader{data: strings.NewReader(test.data), err: stream_err})
+			if err != nil {
+				t.Fatalf("NewReader() error: %v", err)
+			}
+
+			for {
+				_, err := reader.Read()
+				if err == nil {
+					continue
+				}
+				var record_err *RecordError
+				if errors.As(err, &record_err) {
+					t.Fatalf("Read() error = %v, want stream error", err)
+				}
+				if !errors.Is(err, stream_err) {
+					t.Fatalf("Read() error = %v, want %v", err, stream_err)
+				}
+				return
+			}
+		})
+	}
+}
+
+func TestReadCSVWithoutName(t *testing.T) {
+	reader, err := NewReader(FormatCSV, strings.NewReader("external_id,status\na,TODO\n"))
+	if err != nil {
+		t.Fatalf("NewReader() error: %v", err)
+	}
+	_, err = reader.Read()
+	var record_err *RecordError
+	if errors.As(err, &record_err) || !errors.Is(err, domain.ErrRecordInvalid) {
+		t.Fatalf("Read() error = %v, want invalid header", err)
+	}
+}
+
+func TestFormatNotSupported(t *testing.T) {
+	if _, err := NewWriter(Format("XML"), io.Discard); !errors.Is(err, ErrFormatNotSupported) {
+		t.Fatalf("NewWriter() error = %v, want %v", err, ErrFormatNotSupported)
+	}
+	if _, err := NewReader(Format("XML"), strings.NewReader("")); !errors.Is(err, ErrFormatNotSupported) {
+		t.Fatalf("NewReader() error = %v, want %v", err, ErrFormatNotSupported)
+	}
+}
@@ -0,0 +1,66 @@
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"todo-go-grpc/app/task/blobstore"
+	"todo-go-grpc/app/task/repository"
+)
+
+// Purger deletes tasks which stay in trash longer than retention every interval
+type Purger struct {
+	repo      repository.TaskRepository
+	blobStore blobstore.BlobStore
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPurger(repo repository.TaskRepository, blobStore blobstore.BlobStore, retention time.Duration, interval time.Duration, batchSize int) *Purger {
+	return &Purger{
+		repo:      repo,
+		blobStore: blobStore,
+		retention: retention,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run until ctx is done
+func (p *Purger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	for {
+		keys, purged, err := p.repo.PurgeExpired(ctx, time.Now().Add(-p.retention), p.batchSize)
+		if err != nil {
+			log.Printf("Purge trash error: %v", err)
+			return
+		}
+
+		// Attachments of purged tasks are deleted after their rows
+		for _, key := range keys {
+			if err := p.blobStore.Delete(ctx, key); err != nil {
+				log.Printf("Delete blob %v error: %v", key, err)
+			}
+		}
+
+		// Batch is not full, nothing is left to purge
+		if purged < p.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package userservice
+
+import (
+	"context"
+
+	userApi "todo-go-grpc/app/user/api"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
+)
+
+// Users belong to user service, task service asks it instead of reading users table
+type UserService interface {
+	IsExists(ctx context.Context, id int32) (bool, error)
+}
+
+type grpcUserService struct {
+	client userApi.UserHandlerClient
+}
+
+func NewGrpcUserService(conn grpc.ClientConnInterface) UserService {
+	return &grpcUserService{
+		client: userApi.NewUserHandlerClient(conn),
+	}
+}
+
+func (u *grpcUserService) IsExists(ctx context.Context, id int32) (bool, error) {
+	if _, err := u.client.Get(ctx, &userApi.GetReq{Id: id}); err != nil {
+		if grpc_status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
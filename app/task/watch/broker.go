@@ -0,0 +1,13 @@
+package watch
+
+import "context"
+
+// Broker wakes watchers up after tasks change, watchers read the changes from history themselves
+type Broker interface {
+	// Signal watchers of every instance which shares broker
+	Publish()
+	// Get channel which is signalled after tasks change, cancel stops signals and must be called
+	Subscribe() (signal <-chan struct{}, cancel func())
+	// Run until ctx is done
+	Start(ctx context.Context)
+}
@@ -0,0 +1,49 @@
+package watch
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryBroker struct {
+	mutex       sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// Signals only reach watchers of the same process, for single instance runs and tests
+func NewMemoryBroker() Broker {
+	return &memoryBroker{
+		subscribers: map[chan struct{}]struct{}{},
+	}
+}
+
+// Signal is dropped for watcher which is already signalled, one pending signal is enough to read every change
+func (b *memoryBroker) Publish() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *memoryBroker) Subscribe() (<-chan struct{}, func()) {
+	signal := make(chan struct{}, 1)
+
+	b.mutex.Lock()
+	b.subscribers[signal] = struct{}{}
+	b.mutex.Unlock()
+
+	return signal, func() {
+		b.mutex.Lock()
+		delete(b.subscribers, signal)
+		b.mutex.Unlock()
+	}
+}
+
+func (b *memoryBroker) Start(ctx context.Context) {
+	<-ctx.Done()
+}
@@ -0,0 +1,86 @@
+package watch
+
+import (
+	"context"
+	"log"
+	"time"
+	"todo-go-grpc/app/dbservice"
+
+	"github.com/jackc/pgx/v4/stdlib"
+)
+
+// Channel of Postgres notifications which carry changes of tasks
+const channel = "task_changes"
+
+type postgresBroker struct {
+	Conn dbservice.Database
+	// Watchers of this instance are signalled when notification arrives
+	local Broker
+	// How long to wait before listening again after connection is lost
+	retryInterval time.Duration
+}
+
+// Signals go through Postgres NOTIFY, so they reach watchers of every instance listening to the same database
+func NewPostgresBroker(conn dbservice.Database, retryInterval time.Duration) Broker {
+	return &postgresBroker{
+		Conn:          conn,
+		local:         NewMemoryBroker(),
+		retryInterval: retryInterval,
+	}
+}
+
+// Instance which publishes receives its own notification as well
+func (b *postgresBroker) Publish() {
+	if err := b.Conn.Db.Exec("SELECT pg_notify(?, '')", channel).Error; err != nil {
+		log.Printf("Notify %v error: %v", channel, err)
+	}
+}
+
+func (b *postgresBroker) Subscribe() (<-chan struct{}, func()) {
+	return b.local.Subscribe()
+}
+
+// Listen on a dedicated connection until ctx is done, connection is opened again when it is lost
+func (b *postgresBroker) Start(ctx context.Context) {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Listen %v error: %v", channel, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.retryInterval):
+		}
+	}
+}
+
+func (b *postgresBroker) listen(ctx context.Context) error {
+	db, err := b.Conn.Db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driver_conn any) error {
+		pg_conn := driver_conn.(*stdlib.Conn).Conn()
+		if _, err := pg_conn.Exec(ctx, "LISTEN "+channel); err != nil {
+			return err
+		}
+		// Changes made while connection was lost are read by watchers now
+		b.local.Publish()
+
+		for {
+			if _, err := pg_conn.WaitForNotification(ctx); err != nil {
+				return err
+			}
+			b.local.Publish()
+		}
+	})
+}
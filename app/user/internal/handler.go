@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
+	"todo-go-grpc/app/auth"
 	response_service "todo-go-grpc/app/response_handler"
 	api "todo-go-grpc/app/user/api"
 	domain "todo-go-grpc/app/user/domain"
@@ -15,14 +17,23 @@ import (
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
+type Config struct {
+	// Secret shared with services which check tokens
+	TokenSecret []byte
+	// Token expires after ttl since login
+	TokenTTL time.Duration
+}
+
 type server struct {
-	repo repository.UserRepository
+	repo   repository.UserRepository
+	config Config
 	api.UnimplementedUserHandlerServer
 }
 
-func RegisterGrpc(gserver *grpc.Server, repo repository.UserRepository) {
+func RegisterGrpc(gserver *grpc.Server, config Config, repo repository.UserRepository) {
 	userServer := &server{
-		repo: repo,
+		repo:   repo,
+		config: config,
 	}
 
 	api.RegisterUserHandlerServer(gserver, userServer)
@@ -38,7 +49,7 @@ func transferDomainToProto(in domain.User) *api.User {
 	}
 }
 
-func transferProtoToDomain(in api.User) *domain.User {
+func transferProtoToDomain(in *api.User) *domain.User {
 	return &domain.User{
 		ID:        in.Id,
 		Name:      in.Name,
@@ -63,13 +74,12 @@ func (serverInstance *server) Login(ctx context.Context, req *api.LoginReq) (*ap
 		return nil, response_service.ResponseErrorUnknown(err)
 	}
 
-	// TODO: set token here
-
 	user_basic := api.BasicUser{
 		Id:       user.ID,
 		Name:     user.Name,
 		Username: user.Username,
 		Password: user.Password,
+		Token:    auth.NewToken(serverInstance.config.TokenSecret, user.ID, time.Now().Add(serverInstance.config.TokenTTL)),
 	}
 
 	return &user_basic, nil
@@ -120,7 +130,7 @@ func (serverInstance *server) Update(ctx context.Context, req *api.UpdateReq) (*
 		return nil, response_service.ResponseErrorInvalidArgument(err)
 	}
 
-	data := transferProtoToDomain(*req.NewUserInfor)
+	data := transferProtoToDomain(req.NewUserInfor)
 	new_user, err := serverInstance.repo.Update(ctx, req.Id, data)
 
 	if err != nil {
@@ -3,7 +3,9 @@ package main
 import (
 	"log"
 	"net"
+	"os"
 	"strconv"
+	"time"
 	"todo-go-grpc/app/dbservice"
 
 	"google.golang.org/grpc"
@@ -14,6 +16,8 @@ import (
 
 const (
 	port int = 8081
+
+	tokenTTL time.Duration = 24 * time.Hour
 )
 
 func main() {
@@ -27,8 +31,17 @@ func main() {
 
 	db := dbservice.Init()
 
+	// Tokens are signed by AUTH_SECRET, task service checks them with the same secret
+	secret := os.Getenv("AUTH_SECRET")
+	if secret == "" {
+		log.Fatalf("AUTH_SECRET must be set")
+	}
+
 	userRepository := repo.NewUserRepository(*db)
-	service.RegisterGrpc(server, userRepository)
+	service.RegisterGrpc(server, service.Config{
+		TokenSecret: []byte(secret),
+		TokenTTL:    tokenTTL,
+	}, userRepository)
 
 	log.Printf("User service start on port %v", port)
 	if err := server.Serve(listener); err != nil {